@@ -123,9 +123,9 @@ const Nutrition = () => {
             const res = await axios.post(`${base_link}/api/food`, payload);
 
             if (res.status === 200) {
-                const [nutritionGrade, adviceString] = res.data.data as [string, string];
+                const { grade: nutritionGrade, timetable } = res.data.data as { grade: string; timetable: string };
                 setGrade(nutritionGrade);
-                setNutritionAdvice(adviceString);
+                setNutritionAdvice(timetable);
                 toast.success("Nutrition analysis completed successfully!");
             } else {
                 toast.error("Failed to fetch nutrition data. Please try again.");
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/MishraShardendu22/cache"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+// admin routes need ADMIN_TOKEN as a bearer token and are closed when it is
+// unset
+var AdminToken string
+
+func adminOnly(c *fiber.Ctx) error {
+	if AdminToken == "" {
+		return util.ResponseAPI(c, fiber.StatusForbidden, "admin endpoints are disabled", nil, "")
+	}
+	token := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(AdminToken)) != 1 {
+		return util.ResponseAPI(c, fiber.StatusUnauthorized, "invalid admin token", nil, "")
+	}
+	return c.Next()
+}
+
+func cacheStats(c *fiber.Ctx) error {
+	return util.ResponseAPI(c, fiber.StatusOK, "cache stats fetched successfully", util.LLMCache.Stats(), "")
+}
+
+// invalidateCache drops cached LLM results, all of them or one ?kind= (food
+// or plan). Prompt edits already start fresh keys; this clears the old ones
+// and forces re-estimation after model or table changes.
+func invalidateCache(c *fiber.Ctx) error {
+	kind := c.Query("kind")
+	if kind != "" && kind != cache.KindFood && kind != cache.KindPlan {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "kind must be food or plan", nil, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	n, err := util.LLMCache.Invalidate(ctx, kind)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusInternalServerError, "failed to invalidate cache", nil, "")
+	}
+	return util.ResponseAPI(c, fiber.StatusOK, "cache invalidated successfully", fiber.Map{"deleted": n}, "")
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTSecret checks the tokens the main server issues at login
+// (JWT_SECRET_KEY); the meal log is closed when it is unset.
+var JWTSecret string
+
+// authUser returns the user id of the request's bearer token.
+func authUser(c *fiber.Ctx) (string, error) {
+	if JWTSecret == "" {
+		return "", fiber.NewError(fiber.StatusForbidden, "meal log is disabled")
+	}
+	raw, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || raw == "" {
+		return "", fiber.NewError(fiber.StatusUnauthorized, "missing bearer token")
+	}
+
+	token, err := jwt.Parse(raw, func(*jwt.Token) (any, error) {
+		return []byte(JWTSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return "", fiber.NewError(fiber.StatusUnauthorized, "invalid token")
+	}
+
+	claims, _ := token.Claims.(jwt.MapClaims)
+	id, _ := claims["_id"].(string)
+	if id == "" {
+		return "", fiber.NewError(fiber.StatusUnauthorized, "token has no user id")
+	}
+	return id, nil
+}
+
+// authorise checks that the caller is signed in as userID.
+func authorise(c *fiber.Ctx, userID string) error {
+	id, err := authUser(c)
+	if err != nil {
+		return err
+	}
+	if id != userID {
+		return fiber.NewError(fiber.StatusForbidden, "token belongs to another user")
+	}
+	return nil
+}
+
+// ownLog lets a user reach only their own /api/log/:user routes.
+func ownLog(c *fiber.Ctx) error {
+	if err := authorise(c, c.Params("user")); err != nil {
+		return util.ResponseAPI(c, errorStatus(err), err.Error(), nil, "")
+	}
+	return c.Next()
+}
+
+// errorStatus is the status of a *fiber.Error, or 400 for anything else.
+func errorStatus(err error) int {
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return fe.Code
+	}
+	return fiber.StatusBadRequest
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signed(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestOwnLog(t *testing.T) {
+	defer func(s string) { JWTSecret = s }(JWTSecret)
+	JWTSecret = "secret"
+
+	app := fiber.New()
+	app.Get("/api/log/:user", ownLog, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	cases := []struct {
+		name  string
+		user  string
+		token string
+		want  int
+	}{
+		{"own log", "u1", signed(t, "secret", jwt.MapClaims{"_id": "u1", "role": "user"}), fiber.StatusOK},
+		{"another user's log", "u2", signed(t, "secret", jwt.MapClaims{"_id": "u1", "role": "user"}), fiber.StatusForbidden},
+		{"wrong secret", "u1", signed(t, "other", jwt.MapClaims{"_id": "u1"}), fiber.StatusUnauthorized},
+		{"no user id", "u1", signed(t, "secret", jwt.MapClaims{"role": "user"}), fiber.StatusUnauthorized},
+		{"no token", "u1", "", fiber.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/api/log/"+tc.user, nil)
+		if tc.token != "" {
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+tc.token)
+		}
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != tc.want {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.want, res.StatusCode)
+		}
+	}
+
+	JWTSecret = ""
+	res, _ := app.Test(httptest.NewRequest("GET", "/api/log/u1", nil))
+	if res.StatusCode != fiber.StatusForbidden {
+		t.Errorf("Expected the log to be closed without a secret, got %d", res.StatusCode)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/MishraShardendu22/batch"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+// batchScore scores a whole catalogue in one call. The body is either a JSON
+// array of products (the /api/calculate-nutrition fields plus Name), a text/csv
+// body, or a multipart upload with the CSV in a "file" field. ?version= sets
+// the default algorithm and ?format=csv returns the results as a download.
+func batchScore(c *fiber.Ctx) error {
+	def, ok := models.ParseVersion(c.Query("version"))
+	if !ok {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid algorithm version", nil, "")
+	}
+
+	var rows []models.BatchRow
+	var bad []models.BatchResult
+	var err error
+
+	contentType := strings.ToLower(string(c.Request().Header.ContentType()))
+	switch {
+	case strings.HasPrefix(contentType, fiber.MIMEMultipartForm):
+		fh, ferr := c.FormFile("file")
+		if ferr != nil {
+			return util.ResponseAPI(c, fiber.StatusBadRequest, "csv upload needs a file field", nil, "")
+		}
+		f, ferr := fh.Open()
+		if ferr != nil {
+			return util.ResponseAPI(c, fiber.StatusBadRequest, "could not open uploaded file", nil, "")
+		}
+		defer f.Close()
+		rows, bad, err = batch.ParseCSV(f)
+	case strings.HasPrefix(contentType, "text/csv"):
+		rows, bad, err = batch.ParseCSV(bytes.NewReader(c.Body()))
+	default:
+		if err = c.BodyParser(&rows); err == nil {
+			if len(rows) > batch.MaxRows {
+				return util.ResponseAPI(c, fiber.StatusBadRequest, "too many rows in one batch", nil, "")
+			}
+			for i := range rows {
+				rows[i].Line = i + 1
+			}
+		}
+	}
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+	if len(rows)+len(bad) == 0 {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "batch is empty", nil, "")
+	}
+
+	results := batch.SortByLine(append(batch.Score(context.Background(), rows, def, batch.DefaultWorkers), bad...))
+
+	if c.Query("format") == "csv" {
+		var buf bytes.Buffer
+		if err := batch.WriteCSV(&buf, results); err != nil {
+			return util.ResponseAPI(c, fiber.StatusInternalServerError, "failed to write csv", nil, "")
+		}
+		c.Attachment("nutrition-scores.csv")
+		return c.Status(fiber.StatusOK).Send(buf.Bytes())
+	}
+
+	return util.ResponseAPI(c, fiber.StatusOK, "batch scored successfully", results, "")
+}
@@ -0,0 +1,76 @@
+package batch
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/score"
+)
+
+// DefaultWorkers bounds how many rows are scored at once.
+const DefaultWorkers = 8
+
+// MaxRows keeps a single upload from tying up the server.
+const MaxRows = 5000
+
+// Validate applies the same checks as /api/calculate-nutrition to one row.
+// An empty version falls back to def.
+func Validate(row models.BatchRow, def models.Version) (models.ScoreType, models.Version, error) {
+	return cal.Validate(row.NutritionalData, row.ScoreType, row.Version, def)
+}
+
+// Score grades every row with at most workers running at once. Results keep
+// the input order; a bad row gets an error instead of a score and never fails
+// the batch.
+func Score(ctx context.Context, rows []models.BatchRow, def models.Version, workers int) []models.BatchResult {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	results := make([]models.BatchResult, len(rows))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, row := range rows {
+		if ctx.Err() != nil {
+			results[i] = models.BatchResult{Line: row.Line, Name: row.Name, Error: ctx.Err().Error()}
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, row models.BatchRow) {
+			defer func() { <-sem; wg.Done() }()
+			results[i] = scoreRow(row, def)
+		}(i, row)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func scoreRow(row models.BatchRow, def models.Version) models.BatchResult {
+	res := models.BatchResult{Line: row.Line, Name: row.Name}
+
+	st, v, err := Validate(row, def)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	ns := cal.Calculate(row.NutritionalData, st, v)
+	res.ScoreType = ns.ScoreType.String()
+	res.Version = ns.Version
+	res.Score = &ns
+	res.Grade = score.GetGrade(ns.Value, ns.ScoreType, ns.Version)
+	return res
+}
+
+// SortByLine puts rows that failed to parse back among the scored rows.
+func SortByLine(results []models.BatchResult) []models.BatchResult {
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+	return results
+}
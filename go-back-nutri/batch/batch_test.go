@@ -0,0 +1,120 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MishraShardendu22/models"
+)
+
+const menu = `name,energy,sugars,fibre,protein,fruits,sodium,saturatedFattyAcids,scoreType,version
+"Dal, tadka",480,2,5,9,0,300,0.5,,
+orange juice,188,9,0.2,0.7,100,1,0,beverage,2023
+samosa,1096,two,2,5,10,420,4,,
+cheddar,1680,0.1,0,25,0,620,21,cheese,
+`
+
+func TestParseAndScoreCSV(t *testing.T) {
+	rows, bad, err := ParseCSV(strings.NewReader(menu))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0].Name != "Dal, tadka" {
+		t.Fatalf("Expected 3 rows starting with the quoted name, got %+v", rows)
+	}
+	if len(bad) != 1 || bad[0].Line != 4 || bad[0].Name != "samosa" {
+		t.Errorf("Expected the samosa row on line 4 to fail, got %+v", bad)
+	}
+
+	results := SortByLine(append(Score(context.Background(), rows, models.V2017, 2), bad...))
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+	for i, line := range []int{2, 3, 4, 5} {
+		if results[i].Line != line {
+			t.Errorf("Expected line %d at %d, got %d", line, i, results[i].Line)
+		}
+	}
+	if results[1].Version != models.V2023 || results[1].ScoreType != "beverage" {
+		t.Errorf("Expected the juice scored as a 2023 beverage, got %+v", results[1])
+	}
+	if results[3].ScoreType != "cheese" || results[3].Grade == "" {
+		t.Errorf("Expected a graded cheese, got %+v", results[3])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 5 {
+		t.Errorf("Expected a header and 4 lines, got %d", lines)
+	}
+}
+
+func TestParseCSVMissingColumn(t *testing.T) {
+	if _, _, err := ParseCSV(strings.NewReader("name,energy\nrice,540\n")); err == nil {
+		t.Error("Expected an error for a header without the nutrient columns")
+	}
+}
+
+// failingReader returns the header, then an I/O error
+type failingReader struct{ header bool }
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if !r.header {
+		r.header = true
+		return copy(p, "name,energy,sugars,fibre,protein,fruits,sodium,saturatedFattyAcids\n"), nil
+	}
+	return 0, errors.New("connection reset")
+}
+
+func TestParseCSVReadError(t *testing.T) {
+	if _, _, err := ParseCSV(&failingReader{}); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("Expected the read error back, got %v", err)
+	}
+}
+
+func TestParseCSVRejectsNonFinite(t *testing.T) {
+	in := "name,energy,sugars,fibre,protein,fruits,sodium,saturatedFattyAcids\nx,NaN,0,0,0,0,0,0\ny,100,Inf,0,0,0,0,0\n"
+	rows, bad, err := ParseCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 0 || len(bad) != 2 {
+		t.Errorf("Expected both rows rejected, got %d rows and %+v", len(rows), bad)
+	}
+}
+
+func TestScoreRowErrors(t *testing.T) {
+	rows := []models.BatchRow{
+		{Line: 1, Name: "bad type", ScoreType: "snack"},
+		{Line: 2, Name: "negative", NutritionalData: models.NutritionalData{Sugars: -1}},
+		{Line: 3, Name: "negative fat", NutritionalData: models.NutritionalData{Fat: -1}},
+	}
+	for _, r := range Score(context.Background(), rows, models.V2017, 0) {
+		if r.Error == "" || r.Score != nil {
+			t.Errorf("Expected a row error without a score, got %+v", r)
+		}
+	}
+}
+
+func TestParseJSONL(t *testing.T) {
+	in := `{"Name": "dal", "Energy": 480, "Sugars": 2, "Fibre": 5, "Protein": 9, "Sodium": 300}
+
+{"Name": "samosa", "Energy": "lots"}
+{"Name": "orange juice", "Energy": 188, "Sugars": 9, "Fruits": 100, "ScoreType": "beverage"}
+`
+	rows, bad, err := ParseJSONL(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1].Line != 4 || rows[1].ScoreType != "beverage" {
+		t.Errorf("Expected 2 rows with the juice on line 4, got %+v", rows)
+	}
+	if len(bad) != 1 || bad[0].Line != 3 {
+		t.Errorf("Expected line 3 to fail, got %+v", bad)
+	}
+}
@@ -0,0 +1,120 @@
+package batch
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/MishraShardendu22/models"
+)
+
+// Columns is the CSV header. name and the seven nutrient columns are
+// required; scoreType and version may be left out or blank.
+var Columns = []string{"name", "energy", "sugars", "fibre", "protein", "fruits", "sodium", "saturatedFattyAcids", "scoreType", "version"}
+
+var required = Columns[:8]
+
+// ParseCSV reads rows by header name, in any column order. Rows that can't be
+// read come back as results with an error so they are reported alongside the
+// scored rows; only a missing header or a broken file fails the whole upload.
+func ParseCSV(r io.Reader) ([]models.BatchRow, []models.BatchResult, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading csv header: %w", err)
+	}
+	index := map[string]int{}
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, col := range required {
+		if _, ok := index[strings.ToLower(col)]; !ok {
+			return nil, nil, fmt.Errorf("csv is missing the %q column", col)
+		}
+	}
+
+	var rows []models.BatchRow
+	var bad []models.BatchResult
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// only a bad quote is the row's fault; I/O errors end the upload
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) || !errors.Is(pe.Err, csv.ErrQuote) && !errors.Is(pe.Err, csv.ErrBareQuote) {
+				return nil, nil, fmt.Errorf("reading csv: %w", err)
+			}
+			bad = append(bad, models.BatchResult{Line: pe.StartLine, Error: err.Error()})
+			continue
+		}
+		// quoted fields may span lines, so ask the reader where the row began
+		line, _ := cr.FieldPos(0)
+		if len(rows)+len(bad) >= MaxRows {
+			return nil, nil, fmt.Errorf("csv has more than %d rows", MaxRows)
+		}
+
+		get := func(col string) string {
+			if i, ok := index[strings.ToLower(col)]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+
+		row := models.BatchRow{Line: line, Name: get("name"), ScoreType: get("scoreType"), Version: get("version")}
+		values := make([]float64, 0, 7)
+		var rowErr error
+		for _, col := range required[1:] {
+			f, err := strconv.ParseFloat(get(col), 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				rowErr = fmt.Errorf("column %s: %q is not a number", col, get(col))
+				break
+			}
+			values = append(values, f)
+		}
+		if rowErr != nil {
+			bad = append(bad, models.BatchResult{Line: line, Name: row.Name, Error: rowErr.Error()})
+			continue
+		}
+
+		row.Energy = models.EnergyKJ(values[0])
+		row.Sugars = models.SugarGram(values[1])
+		row.Fibre = models.FibreGram(values[2])
+		row.Protein = models.ProteinGram(values[3])
+		row.Fruits = models.FruitsPercent(values[4])
+		row.Sodium = models.SodiumMilligram(values[5])
+		row.SaturatedFattyAcids = models.SaturatedFattyAcidsGram(values[6])
+		rows = append(rows, row)
+	}
+
+	return rows, bad, nil
+}
+
+// WriteCSV writes one line per result, errors included, for download.
+func WriteCSV(w io.Writer, results []models.BatchResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"line", "name", "scoreType", "version", "score", "positive", "negative", "grade", "error"}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		rec := []string{strconv.Itoa(r.Line), r.Name, r.ScoreType, string(r.Version), "", "", "", r.Grade, r.Error}
+		if r.Score != nil {
+			rec[4] = strconv.Itoa(r.Score.Value)
+			rec[5] = strconv.Itoa(r.Score.Positive)
+			rec[6] = strconv.Itoa(r.Score.Negative)
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
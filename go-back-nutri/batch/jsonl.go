@@ -0,0 +1,44 @@
+package batch
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/MishraShardendu22/models"
+)
+
+// ParseJSONL reads one product per line, with the same keys as the JSON
+// batch body. Blank lines are skipped and a line that isn't a JSON object
+// comes back as a failed result, like a bad CSV row.
+func ParseJSONL(r io.Reader) ([]models.BatchRow, []models.BatchResult, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []models.BatchRow
+	var bad []models.BatchResult
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		if len(rows)+len(bad) >= MaxRows {
+			return nil, nil, fmt.Errorf("jsonl has more than %d rows", MaxRows)
+		}
+
+		var row models.BatchRow
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			bad = append(bad, models.BatchResult{Line: line, Error: err.Error()})
+			continue
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading jsonl: %w", err)
+	}
+
+	return rows, bad, nil
+}
@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Backend stores opaque values under string keys. Keys are namespaced as
+// <prefix><kind>:<prompt version>:<hash> so DeletePrefix can drop a kind or
+// a single prompt version.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, val []byte, ttl time.Duration) error
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+	Name() string
+}
+
+const Prefix = "nutri:"
+
+const DefaultTTL = 24 * time.Hour
+
+// Kinds of cached LLM results.
+const (
+	KindFood = "food"
+	KindPlan = "plan"
+)
+
+type counters struct {
+	hits, misses, sets, errors atomic.Int64
+}
+
+// Cache puts a Backend in front of expensive calls and counts hits and
+// misses per kind. A nil *Cache is valid and caches nothing.
+type Cache struct {
+	backend Backend
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	kinds map[string]*counters
+}
+
+func New(b Backend, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{backend: b, ttl: ttl, kinds: map[string]*counters{}}
+}
+
+func (c *Cache) counters(kind string) *counters {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k, ok := c.kinds[kind]
+	if !ok {
+		k = &counters{}
+		c.kinds[kind] = k
+	}
+	return k
+}
+
+// PromptVersion is a short content hash of a system prompt, so editing a
+// prompt starts a fresh set of keys on its own.
+func PromptVersion(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Key builds the cache key for an already normalised input.
+func Key(kind, model, promptVersion, input string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + input))
+	return Prefix + kind + ":" + promptVersion + ":" + hex.EncodeToString(sum[:])
+}
+
+// NormaliseDiet lower-cases a diet string and sorts its items, so "Milk,
+// orange" and "orange,  milk" share a key.
+func NormaliseDiet(diet string) string {
+	parts := strings.FieldsFunc(strings.ToLower(diet), func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+	items := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			items = append(items, p)
+		}
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+// Do returns the cached value for key or calls fetch and stores its result.
+// Failed fetches are never cached, and backend errors only cost the cache,
+// never the call.
+func Do[T any](ctx context.Context, c *Cache, kind, key string, fetch func() (T, error)) (T, error) {
+	if c == nil {
+		return fetch()
+	}
+	n := c.counters(kind)
+
+	if raw, ok, err := c.backend.Get(ctx, key); err != nil {
+		n.errors.Add(1)
+		log.Print("cache get: ", err)
+	} else if ok {
+		var v T
+		if err := json.Unmarshal(raw, &v); err == nil {
+			n.hits.Add(1)
+			return v, nil
+		}
+		n.errors.Add(1)
+	}
+	n.misses.Add(1)
+
+	v, err := fetch()
+	if err != nil {
+		return v, err
+	}
+
+	raw, err := json.Marshal(v)
+	if err == nil {
+		err = c.backend.Set(ctx, key, raw, c.ttl)
+	}
+	if err != nil {
+		n.errors.Add(1)
+		log.Print("cache set: ", err)
+	} else {
+		n.sets.Add(1)
+	}
+	return v, nil
+}
+
+// Invalidate drops every entry of kind, or every entry when kind is empty.
+func (c *Cache) Invalidate(ctx context.Context, kind string) (int, error) {
+	if c == nil {
+		return 0, nil
+	}
+	prefix := Prefix
+	if kind != "" {
+		prefix += kind + ":"
+	}
+	return c.backend.DeletePrefix(ctx, prefix)
+}
+
+type KindStats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	Sets    int64   `json:"sets"`
+	Errors  int64   `json:"errors"`
+	HitRate float64 `json:"hitRate"`
+}
+
+type Stats struct {
+	Backend string               `json:"backend"`
+	TTL     string               `json:"ttl"`
+	Kinds   map[string]KindStats `json:"kinds"`
+	// only reported by backends that track it
+	Entries   *int   `json:"entries,omitempty"`
+	Evictions *int64 `json:"evictions,omitempty"`
+}
+
+// sizer is implemented by backends that can report their size.
+type sizer interface {
+	Len() int
+	Evictions() int64
+}
+
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{Backend: "none", Kinds: map[string]KindStats{}}
+	}
+
+	s := Stats{Backend: c.backend.Name(), TTL: c.ttl.String(), Kinds: map[string]KindStats{}}
+	c.mu.Lock()
+	for kind, n := range c.kinds {
+		ks := KindStats{Hits: n.hits.Load(), Misses: n.misses.Load(), Sets: n.sets.Load(), Errors: n.errors.Load()}
+		if total := ks.Hits + ks.Misses; total > 0 {
+			ks.HitRate = float64(ks.Hits) / float64(total)
+		}
+		s.Kinds[kind] = ks
+	}
+	c.mu.Unlock()
+
+	if sz, ok := c.backend.(sizer); ok {
+		entries, evictions := sz.Len(), sz.Evictions()
+		s.Entries, s.Evictions = &entries, &evictions
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormaliseDiet(t *testing.T) {
+	a := NormaliseDiet("Milk,  orange\n2 Rotis")
+	b := NormaliseDiet("2 rotis; ORANGE, milk,")
+	if a != b || a != "2 rotis,milk,orange" {
+		t.Errorf("Expected the same sorted key, got %q and %q", a, b)
+	}
+}
+
+func TestDoCachesOnlySuccess(t *testing.T) {
+	ctx := context.Background()
+	c := New(NewLRU(10), time.Minute)
+	calls := 0
+	fetch := func() ([]string, error) {
+		calls++
+		return []string{"orange"}, nil
+	}
+
+	key := Key(KindFood, "m", PromptVersion("prompt"), "orange")
+	Do(ctx, c, KindFood, key, fetch)
+	got, err := Do(ctx, c, KindFood, key, fetch)
+	if err != nil || calls != 1 || got[0] != "orange" {
+		t.Errorf("Expected one fetch and a cached value, got %v %v after %d calls", got, err, calls)
+	}
+
+	failing := func() ([]string, error) { calls++; return nil, errors.New("boom") }
+	other := Key(KindFood, "m", PromptVersion("prompt"), "milk")
+	Do(ctx, c, KindFood, other, failing)
+	Do(ctx, c, KindFood, other, failing)
+	if calls != 3 {
+		t.Errorf("Expected failures not to be cached, got %d calls", calls)
+	}
+
+	s := c.Stats().Kinds[KindFood]
+	if s.Hits != 1 || s.Misses != 3 || s.Sets != 1 {
+		t.Errorf("Expected 1 hit, 3 misses and 1 set, got %+v", s)
+	}
+
+	if Key(KindFood, "m", PromptVersion("prompt v2"), "orange") == key {
+		t.Error("Expected a prompt change to change the key")
+	}
+}
+
+func TestLRUEvictsAndExpires(t *testing.T) {
+	ctx := context.Background()
+	l := NewLRU(2)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	l.Set(ctx, "a", []byte("1"), time.Hour)
+	l.Set(ctx, "b", []byte("2"), time.Hour)
+	l.Get(ctx, "a")
+	l.Set(ctx, "c", []byte("3"), time.Hour)
+
+	if _, ok, _ := l.Get(ctx, "b"); ok {
+		t.Error("Expected b to be evicted as least recently used")
+	}
+	if _, ok, _ := l.Get(ctx, "a"); !ok {
+		t.Error("Expected a to survive")
+	}
+	if l.Evictions() != 1 {
+		t.Errorf("Expected 1 eviction, got %d", l.Evictions())
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok, _ := l.Get(ctx, "c"); ok {
+		t.Error("Expected c to expire")
+	}
+}
+
+func TestInvalidateByKind(t *testing.T) {
+	ctx := context.Background()
+	c := New(NewLRU(10), time.Minute)
+	for _, k := range []string{KindFood, KindFood, KindPlan} {
+		key := Key(k, "m", "v1", k+time.Now().String())
+		Do(ctx, c, k, key, func() (int, error) { return 1, nil })
+	}
+
+	if n, _ := c.Invalidate(ctx, KindFood); n != 2 {
+		t.Errorf("Expected 2 food entries dropped, got %d", n)
+	}
+	if n, _ := c.Invalidate(ctx, ""); n != 1 {
+		t.Errorf("Expected the plan entry dropped, got %d", n)
+	}
+
+	var none *Cache
+	if v, err := Do(ctx, none, KindFood, "k", func() (int, error) { return 7, nil }); v != 7 || err != nil {
+		t.Errorf("Expected a nil cache to pass through, got %v %v", v, err)
+	}
+}
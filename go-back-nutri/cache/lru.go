@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LRU is an in-process backend holding at most size entries; the least
+// recently used goes first, and expired entries are dropped when read.
+type LRU struct {
+	mu        sync.Mutex
+	size      int
+	order     *list.List
+	items     map[string]*list.Element
+	evictions int64
+	now       func() time.Time
+}
+
+type lruEntry struct {
+	key     string
+	val     []byte
+	expires time.Time
+}
+
+const DefaultSize = 1000
+
+func NewLRU(size int) *LRU {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &LRU{size: size, order: list.New(), items: map[string]*list.Element{}, now: time.Now}
+}
+
+func (l *LRU) Name() string { return "memory" }
+
+func (l *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := el.Value.(*lruEntry)
+	if l.now().After(e.expires) {
+		l.order.Remove(el)
+		delete(l.items, key)
+		return nil, false, nil
+	}
+	l.order.MoveToFront(el)
+	return e.val, true, nil
+}
+
+func (l *LRU) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expires := l.now().Add(ttl)
+	if el, ok := l.items[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.val, e.expires = val, expires
+		l.order.MoveToFront(el)
+		return nil
+	}
+
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, val: val, expires: expires})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+		l.evictions++
+	}
+	return nil
+}
+
+func (l *LRU) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := 0
+	for key, el := range l.items {
+		if strings.HasPrefix(key, prefix) {
+			l.order.Remove(el)
+			delete(l.items, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *LRU) Evictions() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.evictions
+}
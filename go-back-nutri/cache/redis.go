@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis shares the cache between instances; entries expire through Redis TTLs.
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis takes a redis:// URL, e.g. redis://:password@localhost:6379/0.
+func NewRedis(url string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+	return &Redis{client: redis.NewClient(opts)}, nil
+}
+
+func (r *Redis) Name() string { return "redis" }
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	val, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, val, ttl).Err()
+}
+
+// DeletePrefix scans rather than using KEYS so a large cache doesn't block
+// the server.
+func (r *Redis) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	n := 0
+	iter := r.client.Scan(ctx, 0, prefix+"*", 500).Iterator()
+	var batch []string
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == 500 {
+			deleted, err := r.client.Unlink(ctx, batch...).Result()
+			if err != nil {
+				return n, err
+			}
+			n += int(deleted)
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return n, err
+	}
+	if len(batch) > 0 {
+		deleted, err := r.client.Unlink(ctx, batch...).Result()
+		if err != nil {
+			return n, err
+		}
+		n += int(deleted)
+	}
+	return n, nil
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -5,25 +5,68 @@ import (
 	"github.com/MishraShardendu22/score"
 )
 
-func Calculate(data models.NutritionalData, st models.ScoreType) models.NutritionalScore {
-	energy := score.Energy(data.Energy).GetPoints(st)
-	sugar := score.Sugar(data.Sugars).GetPoints(st)
-	sfa := score.SaturatedFattyAcids(data.SaturatedFattyAcids).GetPoints(st)
-	sodium := score.Sodium(data.Sodium).GetPoints(st)
+// ScoreTypeOf infers the Nutri-Score category from the product flags.
+// Water wins over everything else, since plain water is always an A.
+func ScoreTypeOf(data models.NutritionalData) models.ScoreType {
+	switch {
+	case data.IsWater:
+		return models.Water
+	case data.IsBeverage:
+		return models.Beverage
+	case data.IsCheese:
+		return models.Cheese
+	default:
+		return models.Food
+	}
+}
+
+// Calculate scores data as st under algorithm version v. With models.Auto the
+// type is inferred from the product flags; water is always scored as water.
+func Calculate(data models.NutritionalData, st models.ScoreType, v models.Version) models.NutritionalScore {
+	if st == models.Auto || data.IsWater {
+		st = ScoreTypeOf(data)
+	}
+
+	energy := score.Energy(data.Energy).GetPoints(st, v)
+	sugar := score.Sugar(data.Sugars).GetPoints(st, v)
+	sfa := score.SaturatedFattyAcids(data.SaturatedFattyAcids).GetPoints(st, v)
+	sodium := score.Sodium(data.Sodium).GetPoints(st, v)
 
-	fruits := score.Fruits(data.Fruits).GetPoints(st)
-	fibre := score.Fibre(data.Fibre).GetPoints(st)
-	protein := score.Protein(data.Protein).GetPoints(st)
+	fruits := score.Fruits(data.Fruits).GetPoints(st, v)
+	fibre := score.Fibre(data.Fibre).GetPoints(st, v)
+	protein := score.Protein(data.Protein).GetPoints(st, v)
 
 	negative := energy + sugar + sfa + sodium
-	positive := fruits + fibre + protein
+	positive := fruits + fibre
+
+	levels := score.Levels(v, st)
+
+	var rule models.ScoreRule
+	switch {
+	case negative < levels.ProteinCutOff:
+		rule = models.RuleNegativeBelowCutOff
+	case st == models.Cheese:
+		rule = models.RuleCheese
+	case levels.FruitsExemptProtein && fruits >= levels.FruitsPoints[0]:
+		rule = models.RuleFruitsAtMaximum
+	default:
+		rule = models.RuleProteinExcluded
+	}
+
+	proteinCounted := rule != models.RuleProteinExcluded
+	if proteinCounted {
+		positive += protein
+	}
 
 	final := negative - positive
 
 	return models.NutritionalScore{
-		Value:     final,
-		Positive:  positive,
-		Negative:  negative,
-		ScoreType: st,
+		Value:          final,
+		Positive:       positive,
+		Negative:       negative,
+		ProteinCounted: proteinCounted,
+		Rule:           rule,
+		ScoreType:      st,
+		Version:        v,
 	}
 }
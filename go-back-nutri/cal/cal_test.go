@@ -17,9 +17,145 @@ func TestCalculate(t *testing.T) {
 		SaturatedFattyAcids: 4,
 	}
 
-	result := Calculate(data, models.Food)
+	result := Calculate(data, models.Food, models.V2017)
 
 	if result.Value == 0 {
 		t.Errorf("Expected non-zero score, got %d", result.Value)
 	}
 }
+
+func TestCombineWeightsByGrams(t *testing.T) {
+	items := []models.FoodItem{
+		{Name: "rice", Grams: 300, Data: models.NutritionalData{Energy: 540, Sodium: 0, Protein: 2.7}},
+		{Name: "dal", Grams: 100, Data: models.NutritionalData{Energy: 480, Sodium: 400, Protein: 9}},
+	}
+
+	meal := Combine(items)
+
+	if meal.Grams != 400 {
+		t.Fatalf("Expected 400 grams, got %v", meal.Grams)
+	}
+	if meal.Per100g.Energy != 525 {
+		t.Errorf("Expected weighted energy 525, got %v", meal.Per100g.Energy)
+	}
+	if meal.Per100g.Sodium != 100 {
+		t.Errorf("Expected weighted sodium 100, got %v", meal.Per100g.Sodium)
+	}
+	if meal.Totals.Energy != 2100 {
+		t.Errorf("Expected total energy 2100, got %v", meal.Totals.Energy)
+	}
+	if len(meal.Items) != 2 {
+		t.Errorf("Expected both items kept, got %d", len(meal.Items))
+	}
+}
+
+func TestCalculateInfersScoreType(t *testing.T) {
+	water := Calculate(models.NutritionalData{IsWater: true}, models.Beverage, models.V2017)
+	if water.ScoreType != models.Water {
+		t.Errorf("Expected water to override the requested type, got %v", water.ScoreType)
+	}
+
+	cola := models.NutritionalData{IsBeverage: true, Energy: 180, Sugars: 10.6}
+	if st := Calculate(cola, models.Auto, models.V2017).ScoreType; st != models.Beverage {
+		t.Errorf("Expected beverage to be inferred, got %v", st)
+	}
+	if st := Calculate(cola, models.Food, models.V2017).ScoreType; st != models.Food {
+		t.Errorf("Expected an explicit food to be kept, got %v", st)
+	}
+	if st := Calculate(cola, models.Cheese, models.V2017).ScoreType; st != models.Cheese {
+		t.Errorf("Expected an explicit type to be kept, got %v", st)
+	}
+}
+
+func TestCalculateProteinCutOff(t *testing.T) {
+	// 6 energy + 8 sugar + 7 saturates + 3 sodium = 24 negative points
+	snack := models.NutritionalData{
+		Energy:              2010.5,
+		Sugars:              36.5,
+		SaturatedFattyAcids: 7.5,
+		Sodium:              300,
+		Protein:             8.5,
+		Fibre:               3,
+	}
+
+	result := Calculate(snack, models.Food, models.V2017)
+	if result.ProteinCounted || result.Rule != models.RuleProteinExcluded {
+		t.Fatalf("Expected protein to be excluded, got rule %q", result.Rule)
+	}
+	if result.Value != 24-3 {
+		t.Errorf("Expected score 21, got %d", result.Value)
+	}
+
+	cheese := Calculate(snack, models.Cheese, models.V2017)
+	if cheese.Rule != models.RuleCheese || cheese.Value != 24-3-5 {
+		t.Errorf("Expected cheese to keep protein points, got rule %q score %d", cheese.Rule, cheese.Value)
+	}
+
+	snack.Fruits = 85
+	fruity := Calculate(snack, models.Food, models.V2017)
+	if fruity.Rule != models.RuleFruitsAtMaximum || fruity.Value != 24-3-5-5 {
+		t.Errorf("Expected protein counted at max fruit points, got rule %q score %d", fruity.Rule, fruity.Value)
+	}
+}
+
+func TestCalculateVersions(t *testing.T) {
+	snack := models.NutritionalData{
+		Energy:              2010.5,
+		Sugars:              36.5,
+		SaturatedFattyAcids: 7.5,
+		Sodium:              300,
+		Protein:             8.5,
+		Fibre:               3,
+		Fruits:              85,
+	}
+
+	old := Calculate(snack, models.Food, models.V2017)
+	if old.Value != 11 || old.Rule != models.RuleFruitsAtMaximum {
+		t.Errorf("Expected 2017 score 11 with protein counted, got %d (%q)", old.Value, old.Rule)
+	}
+
+	// 2023 has a steeper sugar scale, a stricter fibre scale and no fruit exemption
+	revised := Calculate(snack, models.Food, models.V2023)
+	if revised.Value != 21 || revised.Rule != models.RuleProteinExcluded {
+		t.Errorf("Expected 2023 score 21 with protein excluded, got %d (%q)", revised.Value, revised.Rule)
+	}
+	if revised.Version != models.V2023 {
+		t.Errorf("Expected version to be recorded, got %q", revised.Version)
+	}
+}
+
+func TestExplainMatchesCalculate(t *testing.T) {
+	data := models.NutritionalData{
+		Energy:              1000,
+		Sugars:              15,
+		Fibre:               2,
+		Protein:             3,
+		Fruits:              45,
+		Sodium:              500,
+		SaturatedFattyAcids: 4,
+	}
+
+	exp := Explain(data, models.Food, models.V2017)
+
+	negative, positive := 0, 0
+	for _, c := range exp.Components {
+		switch {
+		case c.Negative:
+			negative += c.Points
+		case c.Counted:
+			positive += c.Points
+		}
+	}
+	if negative != exp.Score.Negative || positive != exp.Score.Positive {
+		t.Errorf("Expected components to add up to %d/%d, got %d/%d",
+			exp.Score.Negative, exp.Score.Positive, negative, positive)
+	}
+
+	sodium := exp.Components[3]
+	if sodium.Points != 5 || *sodium.Lower != 450 || *sodium.Upper != 540 {
+		t.Errorf("Expected sodium band (450, 540] worth 5 points, got %+v", sodium)
+	}
+	if exp.Grade != "C" {
+		t.Errorf("Expected grade C, got %s", exp.Grade)
+	}
+}
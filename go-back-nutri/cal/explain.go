@@ -0,0 +1,66 @@
+package cal
+
+import (
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/score"
+	"github.com/MishraShardendu22/util"
+)
+
+// Explain breaks a score down per nutrient: the value, the threshold band it
+// fell into and the points it earned, next to the final score and grade.
+func Explain(data models.NutritionalData, st models.ScoreType, v models.Version) models.ScoreExplanation {
+	ns := Calculate(data, st, v)
+	levels := score.Levels(v, ns.ScoreType)
+
+	ranged := func(name string, negative bool, value float64, steps []float64) models.Component {
+		points, lower, upper := util.GetBandFromRange(value, steps)
+		return models.Component{
+			Nutrient:  name,
+			Negative:  negative,
+			Value:     value,
+			Lower:     lower,
+			Upper:     upper,
+			Points:    points,
+			MaxPoints: len(steps),
+			Counted:   true,
+		}
+	}
+
+	components := []models.Component{
+		ranged("energy", true, float64(data.Energy), levels.Energy),
+		ranged("sugars", true, float64(data.Sugars), levels.Sugars),
+		ranged("saturatedFattyAcids", true, float64(data.SaturatedFattyAcids), levels.SaturatedFattyAcids),
+		ranged("sodium", true, float64(data.Sodium), levels.Sodium),
+		fruitsComponent(float64(data.Fruits), levels.Fruits, levels.FruitsPoints),
+		ranged("fibre", false, float64(data.Fibre), levels.Fibre),
+	}
+
+	protein := ranged("protein", false, float64(data.Protein), levels.Protein)
+	protein.Counted = ns.ProteinCounted
+	components = append(components, protein)
+
+	return models.ScoreExplanation{
+		Components: components,
+		Score:      ns,
+		Grade:      score.GetGrade(ns.Value, ns.ScoreType, ns.Version),
+	}
+}
+
+func fruitsComponent(value float64, steps []float64, points []int) models.Component {
+	c := models.Component{Nutrient: "fruits", Value: value, MaxPoints: points[0], Counted: true}
+	for i, l := range steps {
+		if value > l {
+			lower := l
+			c.Lower = &lower
+			c.Points = points[i]
+			if i > 0 {
+				upper := steps[i-1]
+				c.Upper = &upper
+			}
+			return c
+		}
+	}
+	upper := steps[len(steps)-1]
+	c.Upper = &upper
+	return c
+}
@@ -0,0 +1,120 @@
+package cal
+
+import (
+	"github.com/MishraShardendu22/glycaemic"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/score"
+)
+
+// Combine weights every item's per-100g values by the grams eaten so the meal
+// can be scored as a single product.
+func Combine(items []models.FoodItem) models.Meal {
+	meal := models.Meal{Items: items}
+	if len(items) == 0 {
+		return meal
+	}
+
+	isWater, isBeverage, isCheese := true, true, true
+	var totals models.NutritionalData
+	var fruits float64
+	var micros models.Micronutrients
+	var giWeighted, giCarbs float64
+
+	for _, item := range items {
+		factor := item.Grams / 100
+		meal.Grams += item.Grams
+
+		totals.Energy += models.EnergyKJ(float64(item.Data.Energy) * factor)
+		totals.Sugars += models.SugarGram(float64(item.Data.Sugars) * factor)
+		totals.Fibre += models.FibreGram(float64(item.Data.Fibre) * factor)
+		totals.Protein += models.ProteinGram(float64(item.Data.Protein) * factor)
+		totals.Sodium += models.SodiumMilligram(float64(item.Data.Sodium) * factor)
+		totals.SaturatedFattyAcids += models.SaturatedFattyAcidsGram(float64(item.Data.SaturatedFattyAcids) * factor)
+		totals.Fat += models.FatGram(float64(item.Data.Fat) * factor)
+		carbs := float64(item.Data.Carbohydrate) * factor
+		totals.Carbohydrate += models.CarbohydrateGram(carbs)
+		if gi := item.Data.GlycaemicIndex; gi != nil {
+			giWeighted += *gi * carbs
+			giCarbs += carbs
+		}
+		fruits += float64(item.Data.Fruits) * item.Grams
+
+		if m := item.Data.Micronutrients; m != nil {
+			micros = micros.Add(m.Scale(factor))
+			meal.MicronutrientGrams += item.Grams
+		}
+
+		isWater = isWater && item.Data.IsWater
+		isBeverage = isBeverage && (item.Data.IsBeverage || item.Data.IsWater)
+		isCheese = isCheese && item.Data.IsCheese
+	}
+
+	if meal.Grams <= 0 {
+		return meal
+	}
+
+	scale := 100 / meal.Grams
+	totals.IsWater = isWater
+	totals.IsBeverage = isBeverage && !isWater
+	totals.IsCheese = isCheese
+	totals.Fruits = models.FruitsPercent(fruits / meal.Grams)
+	var per100gMicros *models.Micronutrients
+	if meal.MicronutrientGrams > 0 {
+		totals.Micronutrients = &micros
+		scaled := micros.Scale(scale)
+		per100gMicros = &scaled
+	}
+	if giCarbs > 0 {
+		gi := giWeighted / giCarbs
+		totals.GlycaemicIndex = &gi
+	}
+	meal.Totals = totals
+
+	meal.Per100g = models.NutritionalData{
+		IsWater:             totals.IsWater,
+		IsBeverage:          totals.IsBeverage,
+		IsCheese:            totals.IsCheese,
+		Energy:              models.EnergyKJ(float64(totals.Energy) * scale),
+		Sugars:              models.SugarGram(float64(totals.Sugars) * scale),
+		Fibre:               models.FibreGram(float64(totals.Fibre) * scale),
+		Protein:             models.ProteinGram(float64(totals.Protein) * scale),
+		Fruits:              totals.Fruits,
+		Sodium:              models.SodiumMilligram(float64(totals.Sodium) * scale),
+		SaturatedFattyAcids: models.SaturatedFattyAcidsGram(float64(totals.SaturatedFattyAcids) * scale),
+		Fat:                 models.FatGram(float64(totals.Fat) * scale),
+		Carbohydrate:        models.CarbohydrateGram(float64(totals.Carbohydrate) * scale),
+		GlycaemicIndex:      totals.GlycaemicIndex,
+		Micronutrients:      per100gMicros,
+	}
+
+	return meal
+}
+
+// ScoreMeal grades the combined meal and every item in it, so dietitians can
+// see which item dragged the grade down.
+func ScoreMeal(meal models.Meal, v models.Version) models.MealScore {
+	ns := Calculate(meal.Per100g, models.Auto, v)
+
+	items := make([]models.ItemScore, 0, len(meal.Items))
+	for _, item := range meal.Items {
+		is := Calculate(item.Data, models.Auto, v)
+		items = append(items, models.ItemScore{
+			Name:   item.Name,
+			Grams:  item.Grams,
+			Source: item.Source,
+			Data:   item.Data,
+			Score:  is,
+			Grade:  score.GetGrade(is.Value, is.ScoreType, is.Version),
+		})
+	}
+
+	return models.MealScore{
+		Grade:     score.GetGrade(ns.Value, ns.ScoreType, ns.Version),
+		Score:     ns,
+		Grams:     meal.Grams,
+		Per100g:   meal.Per100g,
+		Totals:    meal.Totals,
+		Items:     items,
+		Glycaemic: glycaemic.Meal(meal.Items),
+	}
+}
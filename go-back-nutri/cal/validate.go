@@ -0,0 +1,47 @@
+package cal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/MishraShardendu22/models"
+)
+
+// Validate checks a product the way every scoring endpoint takes it: no
+// negative or non-finite nutrients, a known score type and a known version.
+// An empty version falls back to def.
+func Validate(data models.NutritionalData, scoreType, version string, def models.Version) (models.ScoreType, models.Version, error) {
+	for _, v := range []float64{float64(data.Energy), float64(data.Sugars), float64(data.Fibre), float64(data.Protein),
+		float64(data.Fruits), float64(data.Sodium), float64(data.SaturatedFattyAcids), float64(data.Fat)} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return models.Auto, def, errors.New("nutrient values must be finite numbers")
+		}
+	}
+	if data.Fibre < 0 || data.Energy < 0 || data.Protein < 0 || data.Sugars < 0 ||
+		data.Fruits < 0 || data.Sodium < 0 || data.SaturatedFattyAcids < 0 || data.Fat < 0 {
+		return models.Auto, def, errors.New("nutrient values cannot be negative")
+	}
+	return ParseOptions(scoreType, version, def)
+}
+
+// ParseOptions reads the score type, Auto when empty, and the version, def
+// when empty.
+func ParseOptions(scoreType, version string, def models.Version) (models.ScoreType, models.Version, error) {
+	st := models.Auto
+	if scoreType != "" {
+		var ok bool
+		if st, ok = models.ParseScoreType(scoreType); !ok {
+			return st, def, fmt.Errorf("invalid score type %q", scoreType)
+		}
+	}
+
+	v := def
+	if version != "" {
+		var ok bool
+		if v, ok = models.ParseVersion(version); !ok {
+			return st, v, fmt.Errorf("invalid algorithm version %q", version)
+		}
+	}
+	return st, v, nil
+}
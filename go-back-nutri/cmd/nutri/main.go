@@ -0,0 +1,292 @@
+// Command nutri scores foods and diets with the same code as the HTTP
+// server, for scripting and spreadsheets.
+//
+//	nutri score   -energy 480 -sugars 2 -fibre 5 -protein 9 -sodium 300
+//	nutri explain -energy 480 -sugars 2 -fibre 5 -protein 9 -sodium 300
+//	nutri batch   menu.csv            (or .jsonl, or - for stdin)
+//	nutri estimate "2 rotis, dal and a glass of milk"
+//
+// Every command takes -o table|json|csv and -version 2017|2023. estimate
+// reads the LLM settings from the same environment as the server
+// (LLM_PROVIDER, LLM_BASE_URL, OPENAI_KEY, LLM_MODEL, LLM_TIMEOUT, LLM_RETRIES).
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MishraShardendu22/batch"
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/estimate"
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/provider"
+)
+
+const (
+	exitOK = iota
+	// a row or the estimate failed
+	exitFailed
+	// bad flags or arguments
+	exitUsage
+)
+
+const usage = `usage: nutri <command> [flags]
+
+commands:
+  score     grade one product given per 100 g/ml values
+  explain   show the points behind one product's score
+  batch     grade every row of a CSV or JSONL file ("-" reads stdin)
+  estimate  estimate and grade a free-text diet via the food table and LLM
+
+run nutri <command> -h for the flags of each command
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	var code int
+	var err error
+	switch args[0] {
+	case "score":
+		code, err = scoreCmd(args[1:], stdout, stderr)
+	case "explain":
+		code, err = explainCmd(args[1:], stdout, stderr)
+	case "batch":
+		code, err = batchCmd(args[1:], stdin, stdout, stderr)
+	case "estimate":
+		code, err = estimateCmd(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "nutri: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "nutri:", err)
+	}
+	return code
+}
+
+// common holds the flags every command takes.
+type common struct {
+	format  string
+	version models.Version
+}
+
+func newFlagSet(name string, stderr io.Writer) (*flag.FlagSet, *common, *string) {
+	fs := flag.NewFlagSet("nutri "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	c := &common{}
+	fs.StringVar(&c.format, "o", "table", "output format: table, json or csv")
+	version := fs.String("version", "", "algorithm version, 2017 (default) or 2023")
+	return fs, c, version
+}
+
+func (c *common) parse(version string) error {
+	v, ok := models.ParseVersion(version)
+	if !ok {
+		return fmt.Errorf("invalid algorithm version %q", version)
+	}
+	c.version = v
+
+	switch c.format {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q", c.format)
+}
+
+// productFlags binds the /api/calculate-nutrition fields for score and
+// explain.
+func productFlags(fs *flag.FlagSet) func() models.BatchRow {
+	name := fs.String("name", "", "product name, only echoed back")
+	scoreType := fs.String("type", "", "food, beverage, water or cheese; inferred from the product by default")
+	energy := fs.Float64("energy", 0, "energy in kJ")
+	sugars := fs.Float64("sugars", 0, "sugars in g")
+	fibre := fs.Float64("fibre", 0, "fibre in g")
+	protein := fs.Float64("protein", 0, "protein in g")
+	fruits := fs.Float64("fruits", 0, "fruit, vegetable and legume share in %")
+	sodium := fs.Float64("sodium", 0, "sodium in mg")
+	sfa := fs.Float64("sfa", 0, "saturated fatty acids in g")
+
+	return func() models.BatchRow {
+		row := models.BatchRow{Line: 1, Name: *name, ScoreType: *scoreType}
+		row.Energy = models.EnergyKJ(*energy)
+		row.Sugars = models.SugarGram(*sugars)
+		row.Fibre = models.FibreGram(*fibre)
+		row.Protein = models.ProteinGram(*protein)
+		row.Fruits = models.FruitsPercent(*fruits)
+		row.Sodium = models.SodiumMilligram(*sodium)
+		row.SaturatedFattyAcids = models.SaturatedFattyAcidsGram(*sfa)
+		return row
+	}
+}
+
+func scoreCmd(args []string, stdout, stderr io.Writer) (int, error) {
+	fs, c, version := newFlagSet("score", stderr)
+	product := productFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if err := c.parse(*version); err != nil {
+		return exitUsage, err
+	}
+
+	row := product()
+	if _, _, err := batch.Validate(row, c.version); err != nil {
+		return exitUsage, err
+	}
+
+	results := batch.Score(context.Background(), []models.BatchRow{row}, c.version, 1)
+	return exitOK, writeResults(stdout, c.format, results, true)
+}
+
+func explainCmd(args []string, stdout, stderr io.Writer) (int, error) {
+	fs, c, version := newFlagSet("explain", stderr)
+	product := productFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if err := c.parse(*version); err != nil {
+		return exitUsage, err
+	}
+
+	row := product()
+	st, v, err := batch.Validate(row, c.version)
+	if err != nil {
+		return exitUsage, err
+	}
+
+	return exitOK, writeExplanation(stdout, c.format, cal.Explain(row.NutritionalData, st, v))
+}
+
+func batchCmd(args []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	fs, c, version := newFlagSet("batch", stderr)
+	input := fs.String("in", "", "input format, csv or jsonl; taken from the file extension by default")
+	workers := fs.Int("workers", batch.DefaultWorkers, "rows scored at once")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if err := c.parse(*version); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() != 1 {
+		return exitUsage, errors.New("batch needs exactly one file, or - for stdin")
+	}
+
+	path := fs.Arg(0)
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return exitFailed, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	kind := strings.ToLower(*input)
+	if kind == "" {
+		kind = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	var rows []models.BatchRow
+	var bad []models.BatchResult
+	var err error
+	switch kind {
+	case "jsonl", "ndjson":
+		rows, bad, err = batch.ParseJSONL(r)
+	case "csv", "":
+		rows, bad, err = batch.ParseCSV(r)
+	default:
+		return exitUsage, fmt.Errorf("unknown input format %q, use -in csv or -in jsonl", kind)
+	}
+	if err != nil {
+		return exitFailed, err
+	}
+
+	results := batch.SortByLine(append(batch.Score(context.Background(), rows, c.version, *workers), bad...))
+	if err := writeResults(stdout, c.format, results, false); err != nil {
+		return exitFailed, err
+	}
+
+	// failed rows are in the output already; the exit code lets scripts notice
+	for _, res := range results {
+		if res.Error != "" {
+			return exitFailed, nil
+		}
+	}
+	return exitOK, nil
+}
+
+func estimateCmd(args []string, stdout, stderr io.Writer) (int, error) {
+	fs, c, version := newFlagSet("estimate", stderr)
+	kind := fs.String("provider", os.Getenv("LLM_PROVIDER"), "openrouter, openai or fake; defaults to LLM_PROVIDER")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if err := c.parse(*version); err != nil {
+		return exitUsage, err
+	}
+	diet := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if diet == "" {
+		return exitUsage, errors.New("estimate needs a diet, e.g. nutri estimate \"rice, dal\"")
+	}
+
+	timeout, err := time.ParseDuration(os.Getenv("LLM_TIMEOUT"))
+	if err != nil {
+		timeout = provider.DefaultTimeout
+	}
+	retries, err := strconv.Atoi(os.Getenv("LLM_RETRIES"))
+	if err != nil {
+		retries = provider.DefaultRetries
+	}
+	p, err := provider.New(provider.Config{
+		Kind:    *kind,
+		BaseURL: os.Getenv("LLM_BASE_URL"),
+		APIKey:  os.Getenv("OPENAI_KEY"),
+		Model:   os.Getenv("LLM_MODEL"),
+		Timeout: timeout,
+		Retries: retries,
+	})
+	if err != nil {
+		return exitUsage, err
+	}
+
+	ix, err := foods.Embedded()
+	if err != nil {
+		return exitFailed, fmt.Errorf("food table: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*timeout)
+	defer cancel()
+
+	items, err := estimate.Items(ctx, p, ix, diet)
+	if err != nil {
+		return exitFailed, err
+	}
+
+	ms := cal.ScoreMeal(cal.Combine(items), c.version)
+	return exitOK, writeMeal(stdout, c.format, ms)
+}
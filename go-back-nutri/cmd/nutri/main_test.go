@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/MishraShardendu22/models"
+)
+
+func TestScoreJSON(t *testing.T) {
+	var out, errOut bytes.Buffer
+	code := run(strings.Fields("score -o json -energy 480 -sugars 2 -fibre 5 -protein 9 -sodium 300"), nil, &out, &errOut)
+	if code != exitOK {
+		t.Fatalf("Expected exit 0, got %d: %s", code, errOut.String())
+	}
+
+	var res models.BatchResult
+	if err := json.Unmarshal(out.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Grade != "A" || res.Score == nil || res.Score.Value != -6 {
+		t.Errorf("Expected grade A with score -6, got %+v", res)
+	}
+}
+
+func TestBatchFromStdin(t *testing.T) {
+	in := `{"Name": "dal", "Energy": 480, "Sugars": 2, "Fibre": 5, "Protein": 9, "Sodium": 300}
+{"Name": "samosa", "Energy": "lots"}
+`
+	var out, errOut bytes.Buffer
+	code := run([]string{"batch", "-in", "jsonl", "-o", "csv", "-"}, strings.NewReader(in), &out, &errOut)
+	if code != exitFailed {
+		t.Errorf("Expected exit 1 for the bad row, got %d", code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "1,dal,food,2017,-6,") {
+		t.Errorf("Expected a header and 2 rows starting with dal, got %q", lines)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	cases := [][]string{
+		nil,
+		{"grade"},
+		{"score", "-o", "xml"},
+		{"score", "-version", "1999"},
+		{"explain", "-energy", "-5"},
+		{"batch"},
+		{"estimate", "-provider", "fake"},
+	}
+	for _, args := range cases {
+		var out, errOut bytes.Buffer
+		if code := run(args, nil, &out, &errOut); code != exitUsage {
+			t.Errorf("Expected exit 2 for %q, got %d", args, code)
+		}
+	}
+}
+
+func TestEstimateWithFakeProvider(t *testing.T) {
+	var out, errOut bytes.Buffer
+	code := run([]string{"estimate", "-provider", "fake", "-o", "csv", "rice, dal"}, nil, &out, &errOut)
+	if code != exitOK {
+		t.Fatalf("Expected exit 0, got %d: %s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "rice,150,database") || !strings.Contains(out.String(), "\nmeal,300,") {
+		t.Errorf("Expected table items and a meal row, got %s", out.String())
+	}
+}
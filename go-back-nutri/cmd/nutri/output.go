@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/MishraShardendu22/batch"
+	"github.com/MishraShardendu22/models"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// writeResults prints graded products. single drops the JSON array for the
+// score command, so its output is the same object the batch array holds.
+func writeResults(w io.Writer, format string, results []models.BatchResult, single bool) error {
+	switch format {
+	case formatJSON:
+		if single && len(results) == 1 {
+			return writeJSON(w, results[0])
+		}
+		return writeJSON(w, results)
+	case formatCSV:
+		return batch.WriteCSV(w, results)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tNAME\tTYPE\tVERSION\tSCORE\tGRADE\tERROR")
+	for _, r := range results {
+		value := ""
+		if r.Score != nil {
+			value = strconv.Itoa(r.Score.Value)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Line, r.Name, r.ScoreType, r.Version, value, r.Grade, r.Error)
+	}
+	return tw.Flush()
+}
+
+func bound(b *float64) string {
+	if b == nil {
+		return ""
+	}
+	return num(*b)
+}
+
+func writeExplanation(w io.Writer, format string, ex models.ScoreExplanation) error {
+	switch format {
+	case formatJSON:
+		return writeJSON(w, ex)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"nutrient", "negative", "value", "lower", "upper", "points", "maxPoints", "counted"})
+		for _, c := range ex.Components {
+			cw.Write([]string{c.Nutrient, strconv.FormatBool(c.Negative), num(c.Value), bound(c.Lower), bound(c.Upper),
+				strconv.Itoa(c.Points), strconv.Itoa(c.MaxPoints), strconv.FormatBool(c.Counted)})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NUTRIENT\tSIDE\tVALUE\tBAND\tPOINTS")
+	for _, c := range ex.Components {
+		side := "+"
+		if c.Negative {
+			side = "-"
+		}
+		points := fmt.Sprintf("%d/%d", c.Points, c.MaxPoints)
+		if !c.Counted {
+			points += " (not counted)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s..%s\t%s\n", c.Nutrient, side, num(c.Value), bound(c.Lower), bound(c.Upper), points)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	s := ex.Score
+	_, err := fmt.Fprintf(w, "\nscore %d = %d negative - %d positive (%s, %s, %s), grade %s\n",
+		s.Value, s.Negative, s.Positive, s.ScoreType, s.Version, s.Rule, ex.Grade)
+	return err
+}
+
+func writeMeal(w io.Writer, format string, ms models.MealScore) error {
+	switch format {
+	case formatJSON:
+		return writeJSON(w, ms)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"name", "grams", "source", "score", "grade"})
+		for _, it := range ms.Items {
+			cw.Write([]string{it.Name, num(it.Grams), it.Source, strconv.Itoa(it.Score.Value), it.Grade})
+		}
+		cw.Write([]string{"meal", num(ms.Grams), "", strconv.Itoa(ms.Score.Value), ms.Grade})
+		cw.Flush()
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ITEM\tGRAMS\tSOURCE\tSCORE\tGRADE")
+	for _, it := range ms.Items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", it.Name, num(it.Grams), it.Source, it.Score.Value, it.Grade)
+	}
+	fmt.Fprintf(tw, "meal\t%s\t\t%d\t%s\n", num(ms.Grams), ms.Score.Value, ms.Grade)
+	return tw.Flush()
+}
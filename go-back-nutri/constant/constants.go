@@ -1,5 +1,7 @@
 package constant
 
+import "math"
+
 var FibreLevels = []float64{4.7, 3.7, 2.8, 1.9, 0.9}
 var ProteinLevels = []float64{8, 6.4, 4.8, 3.2, 1.6}
 var ScoreToLetter = []string{"A", "B", "C", "D", "E"}
@@ -9,3 +11,16 @@ var SodiumLevels = []float64{900, 810, 720, 630, 540, 450, 360, 270, 180, 90}
 var SugarsLevelsBeverage = []float64{13.5, 12, 10.5, 9, 7.5, 6, 4.5, 3, 1.5, 0}
 var EnergyLevelsBeverage = []float64{270, 240, 210, 180, 150, 120, 90, 60, 30, 0}
 var EnergyLevels = []float64{3350, 3015, 2680, 2345, 2010, 1675, 1340, 1005, 670, 335}
+
+var FruitsLevels = []float64{80, 60, 40}
+var FruitsPoints = []int{5, 2, 1}
+var FruitsPointsBeverage = []int{10, 4, 2}
+
+// protein points only count below this many negative points, unless fruit
+// points are already at their maximum or the product is a cheese
+const ProteinCutOff = 11
+
+// upper score bound of each letter from A to D, anything above is an E.
+// Only water can be an A among beverages.
+var GradeBounds = []int{-1, 2, 10, 18}
+var GradeBoundsBeverage = []int{math.MinInt, 1, 5, 9}
@@ -0,0 +1,22 @@
+package constant
+
+// Australian/NZ Health Star Rating, category 2 (general foods). Baseline
+// points run to 10 for energy, 30 for saturated fat, 20 for total sugars and
+// 30 for sodium; modifying points to 8 for fruit/veg/nuts/legumes and 15 for
+// protein and fibre.
+var HSREnergyLevels = []float64{3350, 3015, 2680, 2345, 2010, 1675, 1340, 1005, 670, 335}
+var HSRSaturatedFattyAcidsLevels = []float64{30, 29, 28, 27, 26, 25, 24, 23, 22, 21, 20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+var HSRSugarsLevels = []float64{90, 85.5, 81, 76.5, 72, 67.5, 63, 58.5, 54, 49.5, 45, 40.5, 36, 31.5, 27, 22.5, 18, 13.5, 9, 4.5}
+var HSRSodiumLevels = []float64{2700, 2610, 2520, 2430, 2340, 2250, 2160, 2070, 1980, 1890, 1800, 1710, 1620, 1530, 1440, 1350, 1260, 1170, 1080, 990, 900, 810, 720, 630, 540, 450, 360, 270, 180, 90}
+var HSRFruitsLevels = []float64{96, 90, 85, 80, 75, 67, 60, 40}
+var HSRProteinLevels = []float64{24, 22.4, 20.8, 19.2, 17.6, 16, 14.4, 12.8, 11.2, 9.6, 8, 6.4, 4.8, 3.2, 1.6}
+var HSRFibreLevels = []float64{13.5, 12.6, 11.7, 10.8, 9.9, 9, 8.1, 7.2, 6.3, 5.4, 4.5, 3.6, 2.7, 1.8, 0.9}
+
+// protein only counts while baseline points are below this, unless the
+// fruit/veg points reach HSRFruitsExemptPoints
+const HSRProteinCutOff = 13
+const HSRFruitsExemptPoints = 5
+
+// HSRStarBounds[i] is the highest final score that still earns HSRStars[i].
+var HSRStarBounds = []int{-11, -7, -2, 2, 6, 11, 15, 20, 24}
+var HSRStars = []float64{5, 4.5, 4, 3.5, 3, 2.5, 2, 1.5, 1, 0.5}
@@ -0,0 +1,72 @@
+package constant
+
+// Levels is the full set of thresholds one algorithm version uses for one
+// kind of product.
+type Levels struct {
+	Energy              []float64
+	Sugars              []float64
+	SaturatedFattyAcids []float64
+	Sodium              []float64
+	Fibre               []float64
+	Protein             []float64
+	Fruits              []float64
+	FruitsPoints        []int
+	ProteinCutOff       int
+	// 2017 still counts protein past the cut-off when fruit points are maxed
+	FruitsExemptProtein bool
+	GradeBounds         []int
+}
+
+var Food2017 = Levels{
+	Energy:              EnergyLevels,
+	Sugars:              SugarsLevels,
+	SaturatedFattyAcids: SaturatedFattyAcidsLevels,
+	Sodium:              SodiumLevels,
+	Fibre:               FibreLevels,
+	Protein:             ProteinLevels,
+	Fruits:              FruitsLevels,
+	FruitsPoints:        FruitsPoints,
+	ProteinCutOff:       ProteinCutOff,
+	FruitsExemptProtein: true,
+	GradeBounds:         GradeBounds,
+}
+
+var Beverage2017 = Levels{
+	Energy:              EnergyLevelsBeverage,
+	Sugars:              SugarsLevelsBeverage,
+	SaturatedFattyAcids: SaturatedFattyAcidsLevels,
+	Sodium:              SodiumLevels,
+	Fibre:               FibreLevels,
+	Protein:             ProteinLevels,
+	Fruits:              FruitsLevels,
+	FruitsPoints:        FruitsPointsBeverage,
+	ProteinCutOff:       ProteinCutOff,
+	FruitsExemptProtein: true,
+	GradeBounds:         GradeBoundsBeverage,
+}
+
+var Food2023 = Levels{
+	Energy:              EnergyLevels,
+	Sugars:              SugarsLevels2023,
+	SaturatedFattyAcids: SaturatedFattyAcidsLevels,
+	Sodium:              SodiumLevels2023,
+	Fibre:               FibreLevels2023,
+	Protein:             ProteinLevels2023,
+	Fruits:              FruitsLevels,
+	FruitsPoints:        FruitsPoints,
+	ProteinCutOff:       ProteinCutOff,
+	GradeBounds:         GradeBounds2023,
+}
+
+var Beverage2023 = Levels{
+	Energy:              EnergyLevelsBeverage2023,
+	Sugars:              SugarsLevelsBeverage2023,
+	SaturatedFattyAcids: SaturatedFattyAcidsLevels,
+	Sodium:              SodiumLevels2023,
+	Fibre:               FibreLevels2023,
+	Protein:             ProteinLevelsBeverage2023,
+	Fruits:              FruitsLevels,
+	FruitsPoints:        FruitsPointsBeverage2023,
+	ProteinCutOff:       ProteinCutOff,
+	GradeBounds:         GradeBoundsBeverage2023,
+}
@@ -0,0 +1,31 @@
+package constant
+
+// UK FSA front-of-pack traffic lights. Low is at most the first value, high is
+// above the second, medium is in between. Drinks are judged per 100 ml
+// against tighter limits.
+var TrafficFat = [2]float64{3, 17.5}
+var TrafficSaturates = [2]float64{1.5, 5}
+var TrafficSugars = [2]float64{5, 22.5}
+var TrafficSalt = [2]float64{0.3, 1.5}
+
+var TrafficFatDrink = [2]float64{1.5, 8.75}
+var TrafficSaturatesDrink = [2]float64{0.75, 2.5}
+var TrafficSugarsDrink = [2]float64{2.5, 11.25}
+var TrafficSaltDrink = [2]float64{0.3, 0.75}
+
+// adult reference intakes in grams (energy in kJ); a portion over 30% of the
+// reference intake (15% for drinks) is red whatever the per-100g value
+var ReferenceIntakes = map[string]float64{
+	"energy":    8400,
+	"fat":       70,
+	"saturates": 20,
+	"sugars":    90,
+	"salt":      6,
+}
+
+const TrafficPortionHigh = 0.30
+const TrafficPortionHighDrink = 0.15
+
+// portions at or under these sizes are judged on the per-100g value only
+const TrafficPortionFood = 100
+const TrafficPortionDrink = 150
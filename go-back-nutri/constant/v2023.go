@@ -0,0 +1,19 @@
+package constant
+
+import "math"
+
+// 2023 revision for foods and 2024 revision for beverages. Salt moved to a
+// 0-20 point scale (0.2 g salt steps, stored here as sodium mg), sugars to a
+// 0-15 scale, and fibre and protein were rescaled.
+var SugarsLevels2023 = []float64{51, 48, 44, 41, 37, 34, 31, 27, 24, 20, 17, 14, 10, 6.8, 3.4}
+var SodiumLevels2023 = []float64{1600, 1520, 1440, 1360, 1280, 1200, 1120, 1040, 960, 880, 800, 720, 640, 560, 480, 400, 320, 240, 160, 80}
+var FibreLevels2023 = []float64{7.4, 6.3, 5.2, 4.1, 3.0}
+var ProteinLevels2023 = []float64{17, 14, 12, 9.6, 7.2, 4.8, 2.4}
+
+var EnergyLevelsBeverage2023 = []float64{390, 360, 330, 300, 270, 240, 210, 150, 90, 30}
+var SugarsLevelsBeverage2023 = []float64{11, 10, 9, 8, 7, 6, 5, 3.5, 2, 0.5}
+var ProteinLevelsBeverage2023 = []float64{3.0, 2.7, 2.4, 2.1, 1.8, 1.5, 1.2}
+var FruitsPointsBeverage2023 = []int{6, 4, 2}
+
+var GradeBounds2023 = []int{0, 2, 10, 18}
+var GradeBoundsBeverage2023 = []int{math.MinInt, 2, 6, 9}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/MishraShardendu22/energy"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type energyRequest struct {
+	Height   float64 `json:"height"`
+	Weight   float64 `json:"weight"`
+	Age      int     `json:"age"`
+	Gender   string  `json:"gender"`
+	Activity string  `json:"activity"`
+	Goal     string  `json:"goal"`
+}
+
+func energyTargets(c *fiber.Ctx) error {
+	var req energyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid request data", nil, "")
+	}
+
+	in := energy.Input{
+		HeightCm: req.Height,
+		WeightKg: req.Weight,
+		Age:      req.Age,
+		Gender:   req.Gender,
+		Activity: req.Activity,
+		Goal:     req.Goal,
+	}
+	if err := in.Validate(); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	return util.ResponseAPI(c, fiber.StatusOK, "energy targets calculated successfully", energy.Calculate(in), "")
+}
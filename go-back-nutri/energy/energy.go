@@ -0,0 +1,172 @@
+package energy
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/MishraShardendu22/models"
+)
+
+const DefaultAge = 30
+
+var activityFactors = map[string]float64{
+	"sedentary":   1.2,
+	"light":       1.375,
+	"moderate":    1.55,
+	"active":      1.725,
+	"very_active": 1.9,
+}
+
+// kcal added to TDEE per goal, with protein in g per kg body weight and the
+// share of energy from fat
+var goals = map[string]struct {
+	delta, proteinPerKg, fatShare float64
+}{
+	"maintain": {0, 1.0, 0.30},
+	"lose":     {-500, 1.2, 0.25},
+	"gain":     {400, 1.6, 0.25},
+}
+
+// floor for weight-loss targets, below which intake needs supervision
+const minKcalFemale, minKcalMale = 1200, 1500
+
+type Input struct {
+	HeightCm float64
+	WeightKg float64
+	// 0 means unknown and falls back to DefaultAge
+	Age      int
+	Gender   string
+	Activity string
+	Goal     string
+}
+
+func (in Input) Validate() error {
+	if in.HeightCm <= 0 || in.WeightKg <= 0 {
+		return fmt.Errorf("height and weight must be positive")
+	}
+	if in.Age < 0 {
+		return fmt.Errorf("age cannot be negative")
+	}
+	switch strings.ToLower(in.Gender) {
+	case "male", "female", "other":
+	default:
+		return fmt.Errorf("gender must be male, female or other")
+	}
+	if _, ok := activityFactors[activity(in.Activity)]; !ok {
+		return fmt.Errorf("unknown activity level %q", in.Activity)
+	}
+	if _, ok := goals[goal(in.Goal)]; !ok {
+		return fmt.Errorf("unknown goal %q", in.Goal)
+	}
+	return nil
+}
+
+func activity(a string) string {
+	if a == "" {
+		return "sedentary"
+	}
+	return strings.ToLower(a)
+}
+
+func goal(g string) string {
+	if g == "" {
+		return "maintain"
+	}
+	return strings.ToLower(g)
+}
+
+func BMI(heightCm, weightKg float64) float64 {
+	m := heightCm / 100
+	return weightKg / (m * m)
+}
+
+func BMICategory(bmi float64) string {
+	switch {
+	case bmi < 18.5:
+		return "underweight"
+	case bmi < 25:
+		return "normal"
+	case bmi < 30:
+		return "overweight"
+	default:
+		return "obese"
+	}
+}
+
+// MifflinStJeor uses the midpoint of the male and female offsets for "other".
+func MifflinStJeor(heightCm, weightKg float64, age int, gender string) float64 {
+	base := 10*weightKg + 6.25*heightCm - 5*float64(age)
+	switch strings.ToLower(gender) {
+	case "male":
+		return base + 5
+	case "female":
+		return base - 161
+	default:
+		return base - 78
+	}
+}
+
+// HarrisBenedict is the Roza and Shizgal revision, averaged for "other".
+func HarrisBenedict(heightCm, weightKg float64, age int, gender string) float64 {
+	male := 88.362 + 13.397*weightKg + 4.799*heightCm - 5.677*float64(age)
+	female := 447.593 + 9.247*weightKg + 3.098*heightCm - 4.330*float64(age)
+	switch strings.ToLower(gender) {
+	case "male":
+		return male
+	case "female":
+		return female
+	default:
+		return (male + female) / 2
+	}
+}
+
+func Calculate(in Input) models.EnergyTargets {
+	age := in.Age
+	if age == 0 {
+		age = DefaultAge
+	}
+	act, g := activity(in.Activity), goal(in.Goal)
+
+	bmi := BMI(in.HeightCm, in.WeightKg)
+	mifflin := MifflinStJeor(in.HeightCm, in.WeightKg, age, in.Gender)
+	tdee := mifflin * activityFactors[act]
+
+	kcal := tdee + goals[g].delta
+	floor := float64(minKcalMale)
+	if strings.ToLower(in.Gender) == "female" {
+		floor = minKcalFemale
+	}
+	if g == "lose" && kcal < floor {
+		kcal = floor
+	}
+
+	protein := goals[g].proteinPerKg * in.WeightKg
+	fat := kcal * goals[g].fatShare / 9
+	carbs := math.Max(0, (kcal-protein*4-fat*9)/4)
+
+	return models.EnergyTargets{
+		BMI:               round(bmi, 1),
+		BMICategory:       BMICategory(bmi),
+		BMRMifflinStJeor:  math.Round(mifflin),
+		BMRHarrisBenedict: math.Round(HarrisBenedict(in.HeightCm, in.WeightKg, age, in.Gender)),
+		ActivityLevel:     act,
+		ActivityFactor:    activityFactors[act],
+		TDEE:              math.Round(tdee),
+		Goal:              g,
+		Macros: models.MacroTargets{
+			EnergyKcal:   math.Round(kcal),
+			Protein:      math.Round(protein),
+			Carbohydrate: math.Round(carbs),
+			Fat:          math.Round(fat),
+			// 14 g of fibre per 1000 kcal
+			Fibre: math.Round(kcal / 1000 * 14),
+		},
+		AgeAssumed: in.Age == 0,
+	}
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
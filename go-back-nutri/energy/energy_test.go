@@ -0,0 +1,37 @@
+package energy
+
+import "testing"
+
+func TestCalculate(t *testing.T) {
+	in := Input{HeightCm: 180, WeightKg: 80, Age: 30, Gender: "male", Activity: "moderate", Goal: "maintain"}
+	if err := in.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := Calculate(in)
+
+	// 10*80 + 6.25*180 - 5*30 + 5
+	if got.BMRMifflinStJeor != 1780 {
+		t.Errorf("Expected Mifflin-St Jeor BMR 1780, got %v", got.BMRMifflinStJeor)
+	}
+	if got.BMRHarrisBenedict != 1854 {
+		t.Errorf("Expected Harris-Benedict BMR 1854, got %v", got.BMRHarrisBenedict)
+	}
+	if got.TDEE != 2759 {
+		t.Errorf("Expected TDEE 2759, got %v", got.TDEE)
+	}
+	if got.BMI != 24.7 || got.BMICategory != "normal" {
+		t.Errorf("Expected normal BMI 24.7, got %v %s", got.BMI, got.BMICategory)
+	}
+	if got.Macros.Protein != 80 {
+		t.Errorf("Expected 80 g protein, got %v", got.Macros.Protein)
+	}
+
+	lose := Calculate(Input{HeightCm: 150, WeightKg: 45, Age: 70, Gender: "female", Goal: "lose"})
+	if lose.Macros.EnergyKcal != minKcalFemale {
+		t.Errorf("Expected the weight-loss target to stop at %d kcal, got %v", minKcalFemale, lose.Macros.EnergyKcal)
+	}
+	if lose.AgeAssumed {
+		t.Error("Expected age to be marked as provided")
+	}
+}
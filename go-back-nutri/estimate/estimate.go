@@ -0,0 +1,67 @@
+package estimate
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/provider"
+	"github.com/MishraShardendu22/quantity"
+	"github.com/MishraShardendu22/util"
+)
+
+var separators = regexp.MustCompile(`(?i)\s*(?:,|;|\n|\+|&|\band\b|\bwith\b)\s*`)
+
+func SplitDiet(diet string) []string {
+	var parts []string
+	for _, p := range separators.Split(diet, -1) {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// Items scores what it can from the food table and only sends the items it
+// doesn't know to the LLM. Each item records where its numbers came from.
+// Table items are converted to grams from their quantity so "2 cups milk"
+// weighs more than "milk" when the meal is combined.
+func Items(ctx context.Context, p provider.Provider, ix *foods.Index, diet string) ([]models.FoodItem, error) {
+	var items []models.FoodItem
+	var unknown []string
+
+	for _, part := range SplitDiet(diet) {
+		q := quantity.Parse(part)
+		f, ok := ix.Lookup(q.Food)
+		if !ok {
+			unknown = append(unknown, part)
+			continue
+		}
+		items = append(items, models.FoodItem{
+			Name:   f.Name,
+			Grams:  q.Grams(f.Portion()),
+			Data:   f.Data(),
+			Source: models.SourceDatabase,
+		})
+	}
+
+	if len(unknown) == 0 && len(items) > 0 {
+		return items, nil
+	}
+	if len(unknown) == 0 {
+		unknown = []string{diet}
+	}
+
+	estimated, err := util.LLM(ctx, p, strings.Join(unknown, ", "))
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range estimated {
+		item.Source = models.SourceLLM
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,55 @@
+package estimate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/provider"
+)
+
+func TestItemsPrefersDatabase(t *testing.T) {
+	ix, err := foods.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := Items(context.Background(), provider.NewFake(), ix, "rice and dal, dragonfruit smoothie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+
+	sources := map[string]string{}
+	for _, it := range items {
+		sources[it.Name] = it.Source
+	}
+	if sources["rice"] != models.SourceDatabase || sources["dal"] != models.SourceDatabase {
+		t.Errorf("Expected rice and dal from the database, got %v", sources)
+	}
+	if sources["dragonfruit smoothie"] != models.SourceLLM {
+		t.Errorf("Expected the unknown item to go to the LLM, got %v", sources)
+	}
+}
+
+func TestItemsConvertsQuantities(t *testing.T) {
+	ix, err := foods.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := Items(context.Background(), provider.NewFake(), ix, "2 cups milk, 150 g rice, 2 rotis")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]float64{"milk": 2 * 240 * 1.03, "rice": 150, "roti": 80}
+	for _, it := range items {
+		if g := want[it.Name]; it.Grams < g-0.01 || it.Grams > g+0.01 {
+			t.Errorf("Expected %s to weigh %.1f g, got %.1f", it.Name, g, it.Grams)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/MishraShardendu22/health"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+// comment lines sent while waiting so proxies keep the stream open
+var StreamHeartbeat = 15 * time.Second
+
+type scoreEvent struct {
+	models.MealScore
+	Targets        models.EnergyTargets   `json:"targets"`
+	HealthWarnings []models.HealthWarning `json:"healthWarnings"`
+}
+
+type tokenEvent struct {
+	Text string `json:"text"`
+}
+
+type planEvent struct {
+	Plan      models.DietPlan `json:"plan"`
+	Timetable string          `json:"timetable"`
+}
+
+type errorEvent struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+type sseEvent struct {
+	name string
+	data any
+}
+
+// foodStream is /api/food as server-sent events: "score" once the meal is
+// graded, "token" for each piece of the diet plan as the model writes it,
+// "reset" if the plan is regenerated after failing validation, then "plan"
+// and "done". Failures end the stream with an "error" event. Tokens produced
+// before the score is ready are held back so the grade always comes first.
+func foodStream(c *fiber.Ctx) error {
+	in, err := parseFoodRequest(c)
+	if err != nil {
+		return util.ResponseAPI(c, errorStatus(err), err.Error(), nil, "")
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// the fiber ctx is recycled once we return, so only in is used below
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		streamFood(w, in)
+	})
+	return nil
+}
+
+func streamFood(w *bufio.Writer, in foodInput) {
+	// cancelled on return, which is also how a client disconnect (a failed
+	// flush) stops the scoring and the plan generation
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	type scoreResult struct {
+		val models.MealScore
+		err error
+	}
+
+	type planResult struct {
+		val models.DietPlan
+		err error
+	}
+
+	// buffered so neither goroutine blocks once we stop reading
+	scoreCh := make(chan scoreResult, 1)
+	planCh := make(chan planResult, 1)
+	tokens := make(chan sseEvent)
+
+	send := func(e sseEvent) error {
+		select {
+		case tokens <- e:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	go func() {
+		ms, err := scoreDiet(ctx, in)
+		scoreCh <- scoreResult{ms, err}
+	}()
+
+	go func() {
+		plan, err := util.TTStream(ctx, LLMProvider, in.planRequest(), in.checkPlan, util.PlanStream{
+			Token: func(text string) error { return send(sseEvent{"token", tokenEvent{text}}) },
+			Reset: func() error { return send(sseEvent{"reset", struct{}{}}) },
+		})
+		planCh <- planResult{plan, err}
+	}()
+
+	ping := time.NewTicker(StreamHeartbeat)
+	defer ping.Stop()
+
+	var (
+		scored  *models.MealScore
+		pending []sseEvent
+		planned *planResult
+	)
+
+	// finish writes the plan once both halves are in; it reports whether the
+	// stream is over
+	finish := func() bool {
+		if scored == nil || planned == nil {
+			return false
+		}
+		if planned.err != nil {
+			writeError(w, llmErrorStatus(planned.err), planErrorMessage(planned.err))
+			return true
+		}
+		if writeEvent(w, "plan", planEvent{planned.val, planned.val.Text()}) != nil {
+			return true
+		}
+		logMeal(ctx, in, *scored)
+		writeEvent(w, "done", struct{}{})
+		return true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				writeError(w, fiber.StatusGatewayTimeout, "request timed out")
+			}
+			return
+
+		case <-ping.C:
+			if _, err := w.WriteString(": ping\n\n"); err != nil || w.Flush() != nil {
+				return
+			}
+
+		case r := <-scoreCh:
+			if r.err != nil {
+				writeError(w, llmErrorStatus(r.err), r.err.Error())
+				return
+			}
+			scored = &r.val
+			err := writeEvent(w, "score", scoreEvent{
+				MealScore:      r.val,
+				Targets:        in.targets,
+				HealthWarnings: health.Evaluate(r.val.Totals, in.conditions, float64(in.weight)),
+			})
+			if err != nil {
+				return
+			}
+			for _, e := range pending {
+				if writeEvent(w, e.name, e.data) != nil {
+					return
+				}
+			}
+			pending = nil
+			if finish() {
+				return
+			}
+
+		case e := <-tokens:
+			if scored == nil {
+				pending = append(pending, e)
+				continue
+			}
+			if writeEvent(w, e.name, e.data) != nil {
+				return
+			}
+
+		case r := <-planCh:
+			planned = &r
+			if finish() {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w *bufio.Writer, name string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, b); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func writeError(w *bufio.Writer, status int, message string) {
+	if err := writeEvent(w, "error", errorEvent{status, message}); err != nil {
+		log.Print("food stream: ", err)
+	}
+}
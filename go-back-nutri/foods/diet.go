@@ -0,0 +1,142 @@
+package foods
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Dietary patterns, named by what they exclude. Vegetarian has the usual
+// Indian meaning of no meat, fish or eggs, so lacto-vegetarian is another name
+// for it; eggetarian is vegetarian with eggs. Patterns can be combined, e.g.
+// vegetarian and gluten-free.
+const (
+	Omnivore        = ""
+	Vegetarian      = "vegetarian"
+	Eggetarian      = "eggetarian"
+	LactoVegetarian = "lacto-vegetarian"
+	Vegan           = "vegan"
+	Pescatarian     = "pescatarian"
+	Jain            = "jain"
+	Halal           = "halal"
+	GlutenFree      = "gluten-free"
+	LactoseFree     = "lactose-free"
+)
+
+// excludes maps each pattern to the tags it rules out. Tags are the animal
+// and allergen tags of the table plus root, honey, pork and alcohol. Halal
+// can't check how meat was slaughtered, only that there's no pork or alcohol.
+var excludes = map[string][]string{
+	Omnivore:    nil,
+	Vegetarian:  {"meat", "fish", "egg"},
+	Eggetarian:  {"meat", "fish"},
+	Vegan:       {"meat", "fish", "egg", "dairy", "honey"},
+	Pescatarian: {"meat"},
+	Jain:        {"meat", "fish", "egg", "honey", "root"},
+	Halal:       {"pork", "alcohol"},
+	GlutenFree:  {"gluten"},
+	LactoseFree: {"dairy"},
+}
+
+// Patterns lists the accepted pattern names, omnivore left out.
+var Patterns = []string{Vegetarian, Eggetarian, Vegan, Pescatarian, Jain, Halal, GlutenFree, LactoseFree}
+
+// Allergens the table tags foods with.
+var Allergens = []string{"milk", "gluten", "peanut", "tree_nut", "egg", "fish", "soy"}
+
+// aliases are other names accepted for a pattern.
+var aliases = map[string]string{
+	LactoVegetarian: Vegetarian,
+	"omnivore":      Omnivore,
+	"none":          Omnivore,
+}
+
+func exclusions(pattern string) []string {
+	if p, ok := aliases[pattern]; ok {
+		pattern = p
+	}
+	return excludes[pattern]
+}
+
+func ParsePattern(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if p, ok := aliases[name]; ok {
+		name = p
+	}
+	if _, ok := excludes[name]; !ok {
+		return "", fmt.Errorf("unknown dietary pattern %q", name)
+	}
+	return name, nil
+}
+
+func ParseAllergens(names []string) ([]string, error) {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if !slices.Contains(Allergens, n) {
+			return nil, fmt.Errorf("unknown allergen %q, expected one of %s", n, strings.Join(Allergens, ", "))
+		}
+		if !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+// Suits reports whether the food fits the pattern and contains none of the
+// allergens.
+func (f Food) Suits(pattern string, allergens []string) bool {
+	return len(Restrictions{Patterns: []string{pattern}, Allergens: allergens}.Violations(f.AllTags())) == 0
+}
+
+// AllTags are the animal, allergen and other tags of the food together, as
+// Restrictions.Violations takes them.
+func (f Food) AllTags() []string {
+	return slices.Concat(f.Animal, f.Allergens, f.Tags)
+}
+
+// Restrictions are the patterns and allergens a user's plan must respect.
+type Restrictions struct {
+	Patterns  []string
+	Allergens []string
+}
+
+func ParseRestrictions(patterns, allergens []string) (Restrictions, error) {
+	var r Restrictions
+	for _, name := range patterns {
+		p, err := ParsePattern(name)
+		if err != nil {
+			return r, fmt.Errorf("%w, expected one of %s", err, strings.Join(Patterns, ", "))
+		}
+		if p != Omnivore && !slices.Contains(r.Patterns, p) {
+			r.Patterns = append(r.Patterns, p)
+		}
+	}
+
+	var err error
+	r.Allergens, err = ParseAllergens(allergens)
+	return r, err
+}
+
+func (r Restrictions) Empty() bool {
+	return len(r.Patterns) == 0 && len(r.Allergens) == 0
+}
+
+// Violations explains which restriction each of the tags breaks, such as
+// "vegan excludes dairy" or "contains peanut". Nil means the tags are fine.
+func (r Restrictions) Violations(tags []string) []string {
+	var out []string
+	for _, p := range r.Patterns {
+		for _, t := range exclusions(p) {
+			if slices.Contains(tags, t) {
+				out = append(out, p+" excludes "+t)
+			}
+		}
+	}
+	for _, a := range r.Allergens {
+		if slices.Contains(tags, a) {
+			out = append(out, "contains "+a)
+		}
+	}
+	return out
+}
@@ -0,0 +1,260 @@
+package foods
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/quantity"
+	"github.com/MishraShardendu22/util"
+)
+
+//go:embed foods.json
+var embedded string
+
+// Food is one row of the composition table. Nutrient values are per 100 g,
+// energy is stored in kcal and converted to kJ for scoring.
+type Food struct {
+	Name     string   `json:"name"`
+	Synonyms []string `json:"synonyms"`
+	Category string   `json:"category"`
+	// animal products in the food: dairy, egg, meat or fish
+	Animal    []string `json:"animal"`
+	Allergens []string `json:"allergens"`
+	// other tags dietary patterns look at: root, honey, pork or alcohol
+	Tags []string `json:"tags"`
+	// meals the food is usually eaten at; empty means any
+	Meals               []string               `json:"meals"`
+	ServingGrams        float64                `json:"servingGrams"`
+	PieceGrams          float64                `json:"pieceGrams"`
+	Density             float64                `json:"density"`
+	Kcal                float64                `json:"kcal"`
+	Sugars              float64                `json:"sugars"`
+	Fibre               float64                `json:"fibre"`
+	Protein             float64                `json:"protein"`
+	Fruits              float64                `json:"fruits"`
+	Sodium              float64                `json:"sodium"`
+	SaturatedFattyAcids float64                `json:"saturatedFattyAcids"`
+	Fat                 float64                `json:"fat"`
+	Carbohydrate        float64                `json:"carbohydrate"`
+	GI                  *float64               `json:"gi"`
+	IsWater             bool                   `json:"isWater"`
+	IsBeverage          bool                   `json:"isBeverage"`
+	IsCheese            bool                   `json:"isCheese"`
+	Micronutrients      *models.Micronutrients `json:"micronutrients"`
+}
+
+func (f Food) Data() models.NutritionalData {
+	return models.NutritionalData{
+		IsWater:             f.IsWater,
+		IsBeverage:          f.IsBeverage,
+		IsCheese:            f.IsCheese,
+		Energy:              util.EnergyFromKcal(f.Kcal),
+		Sugars:              models.SugarGram(f.Sugars),
+		Fibre:               models.FibreGram(f.Fibre),
+		Protein:             models.ProteinGram(f.Protein),
+		Fruits:              models.FruitsPercent(f.Fruits),
+		Sodium:              models.SodiumMilligram(f.Sodium),
+		SaturatedFattyAcids: models.SaturatedFattyAcidsGram(f.SaturatedFattyAcids),
+		Fat:                 models.FatGram(f.Fat),
+		Carbohydrate:        models.CarbohydrateGram(f.Carbohydrate),
+		GlycaemicIndex:      f.GI,
+		Micronutrients:      f.Micronutrients,
+	}
+}
+
+func (f Food) Portion() quantity.Portion {
+	return quantity.Portion{
+		ServingGrams: f.ServingGrams,
+		PieceGrams:   f.PieceGrams,
+		Density:      f.Density,
+	}
+}
+
+// categories that count towards the fruits, vegetables, legumes and nuts
+// percentage; potatoes and other starchy vegetables don't
+var fruitVegCategories = map[string]bool{
+	"fruit":     true,
+	"vegetable": true,
+	"legume":    true,
+	"nut":       true,
+}
+
+func CountsAsFruitVeg(category string) bool {
+	return fruitVegCategories[strings.ToLower(strings.TrimSpace(category))]
+}
+
+// Index answers name lookups against the table by exact name, synonym, and
+// finally edit distance for misspellings like "bananna".
+type Index struct {
+	foods []Food
+	// keys fuzzy matching may land on
+	fuzzy []string
+	byKey map[string]int
+}
+
+func Load(r io.Reader) (*Index, error) {
+	var foods []Food
+	if err := json.NewDecoder(r).Decode(&foods); err != nil {
+		return nil, fmt.Errorf("decode food table: %w", err)
+	}
+
+	ix := &Index{foods: foods, byKey: map[string]int{}}
+	for i, f := range foods {
+		for j, name := range append([]string{f.Name}, f.Synonyms...) {
+			key := Normalise(name)
+			if _, dup := ix.byKey[key]; dup {
+				return nil, fmt.Errorf("duplicate food name %q", name)
+			}
+			ix.byKey[key] = i
+			// a synonym one letter off another food word, like kheera and
+			// kheer, is more likely that other food than a typo
+			if j == 0 || !nearFoodWord(key) {
+				ix.fuzzy = append(ix.fuzzy, key)
+			}
+		}
+	}
+	sort.Strings(ix.fuzzy)
+
+	return ix, nil
+}
+
+// Embedded loads the table shipped inside the binary.
+func Embedded() (*Index, error) {
+	return Load(strings.NewReader(embedded))
+}
+
+func (ix *Index) Foods() []Food {
+	return ix.foods
+}
+
+func (ix *Index) Lookup(name string) (Food, bool) {
+	key := Normalise(name)
+	if key == "" {
+		return Food{}, false
+	}
+
+	for _, k := range []string{key, singular(key)} {
+		if i, ok := ix.byKey[k]; ok {
+			return ix.foods[i], true
+		}
+	}
+
+	best, bestDist := -1, maxDistance(key)+1
+	for _, k := range ix.fuzzy {
+		if d, ok := distance(key, k); ok && d < bestDist {
+			best, bestDist = ix.byKey[k], d
+		}
+	}
+	if best < 0 {
+		return Food{}, false
+	}
+	return ix.foods[best], true
+}
+
+// Normalise lower-cases a name and drops digits and punctuation so that
+// "2 Oranges!" and "oranges" share a key.
+func Normalise(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r):
+			return unicode.ToLower(r)
+		case unicode.IsSpace(r) || r == '-':
+			return ' '
+		default:
+			return -1
+		}
+	}, name)
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "oes"), strings.HasSuffix(s, "ches"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return strings.TrimSuffix(s, "s")
+	default:
+		return s
+	}
+}
+
+// distance compares names word by word, so a typo may sit in any word but
+// "egg curry" never matches "veg curry": every word has to be within its own
+// limit, short words have none, and the first letter is never the typo.
+func distance(a, b string) (int, bool) {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	if len(wa) != len(wb) {
+		return 0, false
+	}
+
+	total := 0
+	for i := range wa {
+		if wa[i][0] != wb[i][0] {
+			return 0, false
+		}
+		d := edits(wa[i], wb[i])
+		if d > maxDistance(wa[i]) {
+			return 0, false
+		}
+		total += d
+	}
+	return total, true
+}
+
+// nearFoodWord reports whether key is one edit away from a word the
+// ingredient list knows as a different food.
+func nearFoodWord(key string) bool {
+	for _, in := range ingredients {
+		for _, w := range in.words {
+			if w != key && edits(w, key) == 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// names of six letters or fewer get no typo allowance and longer ones a
+// single edit: batter and butter, or pomato and potato, are different foods
+func maxDistance(s string) int {
+	if len(s) <= 6 {
+		return 0
+	}
+	return 1
+}
+
+// edits is the Levenshtein distance with swapping two neighbouring letters
+// counted as one edit, the commonest typo ("vegetabels").
+func edits(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// rows i-2, i-1 and i of the table
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,126 @@
+package foods
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/MishraShardendu22/models"
+)
+
+func TestLookup(t *testing.T) {
+	ix, err := Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"Milk":      "milk",
+		"2 oranges": "orange",
+		"chapati":   "roti",
+		"bananna":   "banana",
+		"Tomatoes":  "tomato",
+		"dahi":      "curd",
+	}
+	for in, want := range cases {
+		f, ok := ix.Lookup(in)
+		if !ok || f.Name != want {
+			t.Errorf("Lookup(%q): expected %q, got %q (found %v)", in, want, f.Name, ok)
+		}
+	}
+
+	// real foods that are a letter or two off a table name or synonym
+	for _, in := range []string{"dragonfruit smoothie", "egg curry", "kheer", "cake", "ham", "toffee", "batter", "mimosa", "pomato"} {
+		if f, ok := ix.Lookup(in); ok {
+			t.Errorf("Lookup(%q): expected no match, got %q", in, f.Name)
+		}
+	}
+	if f, ok := ix.Lookup("mixed vegetabels"); !ok || f.Name != "mixed vegetables" {
+		t.Errorf("Expected a typo in one word of a longer name to match, got %q (found %v)", f.Name, ok)
+	}
+}
+
+func TestSuits(t *testing.T) {
+	ix, err := Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	egg, _ := ix.Lookup("egg")
+	curd, _ := ix.Lookup("curd")
+	roti, _ := ix.Lookup("roti")
+
+	if egg.Suits(Vegetarian, nil) || !egg.Suits(Eggetarian, nil) || egg.Suits(Vegan, nil) {
+		t.Errorf("Expected eggs only in the eggetarian pattern, got animal %v", egg.Animal)
+	}
+	if p, err := ParsePattern("Lacto-Vegetarian"); err != nil || p != Vegetarian {
+		t.Errorf("Expected lacto-vegetarian to mean vegetarian, got %q, %v", p, err)
+	}
+	if !curd.Suits(Vegetarian, nil) || curd.Suits(Vegan, nil) || curd.Suits(Omnivore, []string{"milk"}) {
+		t.Errorf("Expected curd to be lacto-vegetarian and to contain milk, got %+v", curd)
+	}
+	if roti.Suits(Vegan, []string{"gluten"}) {
+		t.Error("Expected roti to be excluded for a gluten allergy")
+	}
+
+	if _, err := ParsePattern("keto"); err == nil {
+		t.Error("Expected an error for an unknown pattern")
+	}
+}
+
+func TestIngredientTags(t *testing.T) {
+	cases := map[string][]string{
+		"Chicken biryani":    {"meat"},
+		"peanut chikki":      {"peanut"},
+		"Aloo gobi":          {"root"},
+		"coconut milk curry": nil,
+		"almond milk":        {"tree_nut"},
+		"paneer tikka":       {"dairy", "milk"},
+		"eggs":               {"egg"},
+		"Cheesecake":         {"dairy", "gluten", "milk"},
+		"cupcakes":           {"gluten"},
+		"butternut squash":   nil,
+		"goat curry":         {"meat"},
+	}
+	for in, want := range cases {
+		if got := IngredientTags(in); !slices.Equal(got, want) {
+			t.Errorf("Expected %v for %q, got %v", want, in, got)
+		}
+	}
+}
+
+func TestCheckPlan(t *testing.T) {
+	ix, err := Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := models.DietPlan{Meals: []models.PlanMeal{
+		{Name: "Breakfast", Items: []models.PlanItem{{Food: "oats"}, {Food: "milk"}, {Food: "banana"}}},
+		{Name: "Lunch", Items: []models.PlanItem{{Food: "dal"}, {Food: "jeera rice"}, {Food: "onion salad"}}},
+	}}
+
+	r, err := ParseRestrictions([]string{"Jain", "gluten-free", "jain"}, []string{"peanut"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := CheckPlan(ix, plan, r)
+	if len(got) != 2 || got[0].Food != "oats" || got[1].Food != "onion salad" {
+		t.Fatalf("Expected oats and the onion salad to be rejected, got %+v", got)
+	}
+	if got[1].Reasons[0] != "jain excludes root" {
+		t.Errorf("Expected the jain reason, got %v", got[1].Reasons)
+	}
+
+	if v := CheckPlan(ix, plan, Restrictions{Patterns: []string{Eggetarian}}); v != nil {
+		t.Errorf("Expected the plan to suit an eggetarian, got %+v", v)
+	}
+
+	eggs := models.DietPlan{Meals: []models.PlanMeal{{Name: "Breakfast", Items: []models.PlanItem{{Food: "boiled egg"}, {Food: "toast"}}}}}
+	if v := CheckPlan(ix, eggs, Restrictions{Patterns: []string{Vegetarian}}); len(v) != 1 || v[0].Reasons[0] != "vegetarian excludes egg" {
+		t.Errorf("Expected the egg to be rejected for a vegetarian, got %+v", v)
+	}
+	if r, err := ParseRestrictions([]string{"Omnivore", "none"}, nil); err != nil || !r.Empty() {
+		t.Errorf("Expected omnivore and none to mean no pattern, got %+v, %v", r, err)
+	}
+	if _, err := ParseRestrictions([]string{"keto"}, nil); err == nil {
+		t.Error("Expected an error for an unknown pattern")
+	}
+}
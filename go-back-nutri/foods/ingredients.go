@@ -0,0 +1,113 @@
+package foods
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/MishraShardendu22/models"
+)
+
+// ingredients maps words found in dish names to the tags they imply, so
+// "chicken biryani" or "peanut chikki" are caught even though only the
+// plain foods are in the table. Words are matched in singular form.
+var ingredients = []struct {
+	tags  []string
+	words []string
+}{
+	{[]string{"meat"}, []string{"meat", "chicken", "murgh", "mutton", "lamb", "goat", "beef", "keema", "turkey", "duck", "salami", "sausage"}},
+	{[]string{"meat", "pork"}, []string{"pork", "bacon", "ham", "lard", "pepperoni", "gelatin", "gelatine"}},
+	{[]string{"fish"}, []string{"fish", "machli", "prawn", "shrimp", "tuna", "salmon", "sardine", "mackerel", "anchovy", "crab", "lobster", "seafood"}},
+	{[]string{"egg"}, []string{"egg", "anda", "omelette", "omelet", "mayonnaise"}},
+	{[]string{"dairy", "milk"}, []string{"milk", "doodh", "paneer", "cheese", "curd", "dahi", "yogurt", "yoghurt", "butter", "buttermilk", "chaas", "ghee", "cream", "lassi", "raita", "khoa", "kheer", "whey"}},
+	{[]string{"gluten"}, []string{"wheat", "atta", "maida", "roti", "chapati", "naan", "kulcha", "paratha", "puri", "bhatura", "bread", "bun", "pasta", "noodle", "semolina", "suji", "rava", "upma", "daliya", "barley", "rye", "oat", "couscous", "seitan", "biscuit", "cake", "pizza"}},
+	{[]string{"peanut"}, []string{"peanut", "groundnut", "moongphali"}},
+	{[]string{"tree_nut"}, []string{"almond", "badam", "cashew", "kaju", "walnut", "akhrot", "pistachio", "pista", "hazelnut", "pecan"}},
+	{[]string{"soy"}, []string{"soy", "soya", "tofu", "edamame"}},
+	{[]string{"root"}, []string{"potato", "aloo", "onion", "pyaz", "garlic", "lahsun", "carrot", "gajar", "beetroot", "radish", "mooli", "ginger", "adrak", "turnip", "yam", "arbi"}},
+	{[]string{"honey"}, []string{"honey"}},
+	{[]string{"alcohol"}, []string{"wine", "beer", "rum", "vodka", "whisky", "whiskey", "brandy", "liquor"}},
+}
+
+// phrases are read before single words so plant milks and nut butters don't
+// count as dairy.
+var phrases = map[string][]string{
+	"coconut milk":  nil,
+	"almond milk":   {"tree_nut"},
+	"cashew milk":   {"tree_nut"},
+	"soy milk":      {"soy"},
+	"oat milk":      {"gluten"},
+	"rice milk":     nil,
+	"peanut butter": {"peanut"},
+	"almond butter": {"tree_nut"},
+	"cocoa butter":  nil,
+	"vegan cheese":  nil,
+}
+
+// falseFriends are compound words that start or end with an ingredient word
+// but don't contain it.
+var falseFriends = []string{"butternut", "honeydew", "crabapple", "breadfruit", "buckwheat", "rootbeer"}
+
+// inWord reports whether the ingredient word w is the word itself or, for
+// words of four letters or more, the start or end of a compound such as
+// "cheesecake" or "cupcake". Shorter words would catch "goat" in "oat".
+func inWord(word, w string) bool {
+	if word == w {
+		return true
+	}
+	if len(w) < 4 || slices.Contains(falseFriends, word) {
+		return false
+	}
+	return strings.HasPrefix(word, w) || strings.HasSuffix(word, w)
+}
+
+// IngredientTags guesses the tags of a dish from the words in its name.
+func IngredientTags(name string) []string {
+	text := " " + Normalise(name) + " "
+
+	var tags []string
+	for phrase, t := range phrases {
+		if strings.Contains(text, " "+phrase+" ") {
+			tags = append(tags, t...)
+			text = strings.ReplaceAll(text, " "+phrase+" ", " ")
+		}
+	}
+
+	for _, word := range strings.Fields(text) {
+		word = singular(word)
+		for _, in := range ingredients {
+			if slices.ContainsFunc(in.words, func(w string) bool { return inWord(word, w) }) {
+				tags = append(tags, in.tags...)
+			}
+		}
+	}
+
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// Tags is what the table knows about the food plus what its name implies.
+func (ix *Index) Tags(name string) []string {
+	tags := IngredientTags(name)
+	if f, ok := ix.Lookup(name); ok {
+		tags = append(tags, f.AllTags()...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// CheckPlan lists every item of the plan that breaks the restrictions.
+func CheckPlan(ix *Index, plan models.DietPlan, r Restrictions) []models.PlanViolation {
+	if r.Empty() {
+		return nil
+	}
+
+	var out []models.PlanViolation
+	for _, m := range plan.Meals {
+		for _, it := range m.Items {
+			if reasons := r.Violations(ix.Tags(it.Food)); len(reasons) > 0 {
+				out = append(out, models.PlanViolation{Meal: m.Name, Food: it.Food, Reasons: reasons})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package glycaemic
+
+import (
+	"math"
+
+	"github.com/MishraShardendu22/models"
+)
+
+const (
+	Low    = "low"
+	Medium = "medium"
+	High   = "high"
+)
+
+// per-serving glycaemic load bands: 10 or less is low, 20 or more is high
+const (
+	LowMax  = 10
+	HighMin = 20
+)
+
+// Classify bands the glycaemic load of a single meal or serving.
+func Classify(gl float64) string {
+	switch {
+	case gl <= LowMax:
+		return Low
+	case gl < HighMin:
+		return Medium
+	default:
+		return High
+	}
+}
+
+// Load is GI × grams of available carbohydrate / 100.
+func Load(gi, carbohydrate float64) float64 {
+	return gi * carbohydrate / 100
+}
+
+// Meal adds up the glycaemic load of every item. Items without a GI add their
+// carbohydrate but no load, which Coverage makes visible.
+func Meal(items []models.FoodItem) models.GlycaemicLoad {
+	out := models.GlycaemicLoad{Items: make([]models.ItemGlycaemicLoad, 0, len(items))}
+	var giWeighted, giCarbs float64
+
+	for _, item := range items {
+		carbs := float64(item.Data.Carbohydrate) * item.Grams / 100
+		il := models.ItemGlycaemicLoad{
+			Name:         item.Name,
+			Grams:        item.Grams,
+			Carbohydrate: round(carbs),
+			GI:           item.Data.GlycaemicIndex,
+		}
+		out.Carbohydrate += carbs
+
+		if gi := item.Data.GlycaemicIndex; gi != nil {
+			gl := Load(*gi, carbs)
+			out.GL += gl
+			giWeighted += *gi * carbs
+			giCarbs += carbs
+			rounded := round(gl)
+			il.GL = &rounded
+		}
+		out.Items = append(out.Items, il)
+	}
+
+	if giCarbs > 0 {
+		gi := round(giWeighted / giCarbs)
+		out.GI = &gi
+	}
+	if out.Carbohydrate > 0 {
+		out.Coverage = round(giCarbs / out.Carbohydrate)
+	} else {
+		out.Coverage = 1
+	}
+	out.GL = round(out.GL)
+	out.Carbohydrate = round(out.Carbohydrate)
+	out.Class = Classify(out.GL)
+	return out
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
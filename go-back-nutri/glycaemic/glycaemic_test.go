@@ -0,0 +1,41 @@
+package glycaemic
+
+import (
+	"testing"
+
+	"github.com/MishraShardendu22/models"
+)
+
+func gi(v float64) *float64 { return &v }
+
+func TestMeal(t *testing.T) {
+	items := []models.FoodItem{
+		{Name: "rice", Grams: 150, Data: models.NutritionalData{Carbohydrate: 28.2, GlycaemicIndex: gi(73)}},
+		{Name: "apple", Grams: 150, Data: models.NutritionalData{Carbohydrate: 13.8, GlycaemicIndex: gi(36)}},
+		{Name: "paneer", Grams: 100, Data: models.NutritionalData{Carbohydrate: 1.2}},
+	}
+
+	got := Meal(items)
+	// 42.3 g at GI 73 plus 20.7 g at GI 36
+	if got.GL != 38.3 || got.Class != High {
+		t.Errorf("Expected a high load of 38.3, got %v %s", got.GL, got.Class)
+	}
+	if got.GI == nil || *got.GI != 60.8 {
+		t.Errorf("Expected a carbohydrate-weighted GI of 60.8, got %v", got.GI)
+	}
+	if got.Carbohydrate != 64.2 || got.Coverage != 1 {
+		t.Errorf("Expected 64.2 g carbohydrate with coverage rounding to 1, got %v at %v", got.Carbohydrate, got.Coverage)
+	}
+	if got.Items[2].GL != nil {
+		t.Errorf("Expected no load for paneer without a GI, got %v", *got.Items[2].GL)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	cases := map[float64]string{0: Low, 10: Low, 10.1: Medium, 19.9: Medium, 20: High}
+	for gl, want := range cases {
+		if got := Classify(gl); got != want {
+			t.Errorf("Expected %s for %v, got %s", want, gl, got)
+		}
+	}
+}
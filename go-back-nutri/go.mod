@@ -2,9 +2,16 @@ module github.com/MishraShardendu22
 
 go 1.24.4
 
-require github.com/gofiber/fiber/v2 v2.52.8
+require (
+	github.com/gofiber/fiber/v2 v2.52.8
+	github.com/golang-jwt/jwt/v5 v5.2.1
+	github.com/redis/go-redis/v9 v9.7.0
+	go.etcd.io/bbolt v1.3.11
+)
 
 require (
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	golang.org/x/net v0.41.0 // indirect
 	golang.org/x/time v0.12.0 // indirect
 )
@@ -0,0 +1,224 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/MishraShardendu22/glycaemic"
+	"github.com/MishraShardendu22/models"
+)
+
+// Condition uses the go-server model.HealthIssue vocabulary so survey answers
+// can be passed through unchanged.
+type Condition string
+
+const (
+	Cancer                 Condition = "cancer"
+	HeartDisease           Condition = "heart_disease"
+	Diabetes               Condition = "diabetes"
+	Hypertension           Condition = "hypertension"
+	Asthma                 Condition = "asthma"
+	Stroke                 Condition = "stroke"
+	Epilepsy               Condition = "epilepsy"
+	HIV                    Condition = "HIV"
+	Hepatitis              Condition = "hepatitis"
+	Tuberculosis           Condition = "tuberculosis"
+	Malaria                Condition = "malaria"
+	ChronicKidneyDisease   Condition = "chronic_kidney_disease"
+	AutoimmuneDisorders    Condition = "autoimmune_disorders"
+	BloodClottingDisorders Condition = "blood_clotting_disorders"
+	MentalIllness          Condition = "mental_illness"
+	Pregnancy              Condition = "pregnancy"
+	SevereAllergies        Condition = "severe_allergies"
+	LiverDisease           Condition = "liver_disease"
+	RecentInfections       Condition = "recent_infections"
+	DrugAbuse              Condition = "drug_abuse"
+)
+
+var known = map[Condition]bool{
+	Cancer: true, HeartDisease: true, Diabetes: true, Hypertension: true,
+	Asthma: true, Stroke: true, Epilepsy: true, HIV: true, Hepatitis: true,
+	Tuberculosis: true, Malaria: true, ChronicKidneyDisease: true,
+	AutoimmuneDisorders: true, BloodClottingDisorders: true, MentalIllness: true,
+	Pregnancy: true, SevereAllergies: true, LiverDisease: true,
+	RecentInfections: true, DrugAbuse: true,
+}
+
+// a meal is judged against a third of the daily limit
+const MealShare = 1.0 / 3
+
+// Parse checks every code against the survey vocabulary and drops duplicates.
+func Parse(codes []string) ([]Condition, error) {
+	seen := map[Condition]bool{}
+	out := make([]Condition, 0, len(codes))
+	for _, code := range codes {
+		c := Condition(code)
+		if !known[c] {
+			return nil, fmt.Errorf("unknown health condition %q", code)
+		}
+		if !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// limit is a daily ceiling for one nutrient. perKg limits are multiplied by
+// body weight.
+type limit struct {
+	nutrient string
+	unit     string
+	daily    float64
+	perKg    bool
+	get      func(models.NutritionalData) (float64, bool)
+	advice   string
+}
+
+func sodium(d models.NutritionalData) (float64, bool)  { return float64(d.Sodium), true }
+func sugars(d models.NutritionalData) (float64, bool)  { return float64(d.Sugars), true }
+func satFat(d models.NutritionalData) (float64, bool)  { return float64(d.SaturatedFattyAcids), true }
+func protein(d models.NutritionalData) (float64, bool) { return float64(d.Protein), true }
+func carbs(d models.NutritionalData) (float64, bool)   { return float64(d.Carbohydrate), true }
+
+// glycaemicLoad treats carbohydrate from items without a GI like the rest of
+// the meal, since the totals only carry the carb-weighted GI.
+func glycaemicLoad(d models.NutritionalData) (float64, bool) {
+	if d.GlycaemicIndex == nil {
+		return 0, false
+	}
+	return glycaemic.Load(*d.GlycaemicIndex, float64(d.Carbohydrate)), true
+}
+
+func potassium(d models.NutritionalData) (float64, bool) {
+	if d.Micronutrients == nil {
+		return 0, false
+	}
+	return d.Micronutrients.Potassium, true
+}
+
+// rules holds the conditions that change what a meal should contain; the rest
+// of the vocabulary is accepted but has no nutrient limits yet.
+var rules = map[Condition][]limit{
+	Hypertension: {
+		{"sodium", "mg", 1500, false, sodium, "keep sodium under 1500 mg a day; avoid added salt, pickles and processed meat"},
+	},
+	Diabetes: {
+		{"sugars", "g", 25, false, sugars, "keep sugars under 25 g a day; prefer whole grains, legumes and high-fibre foods over sweets and juices"},
+		{"carbohydrate", "g", 180, false, carbs, "keep carbohydrate to about 60 g a meal (180 g a day), spread evenly across meals"},
+		// three meals at the high per-serving band, so a single meal warns
+		// once its load is high
+		{"glycaemicLoad", "", glycaemic.HighMin / MealShare, false, glycaemicLoad, "keep each meal's glycaemic load under 20; prefer low-GI grains and legumes and pair carbohydrate with protein and fibre"},
+	},
+	ChronicKidneyDisease: {
+		{"potassium", "mg", 2000, false, potassium, "keep potassium under 2000 mg a day; limit bananas, potatoes, tomatoes and oranges"},
+		{"protein", "g", 0.8, true, protein, "keep protein under 0.8 g per kg body weight a day"},
+		{"sodium", "mg", 2000, false, sodium, "keep sodium under 2000 mg a day"},
+	},
+	HeartDisease: {
+		{"saturatedFattyAcids", "g", 13, false, satFat, "keep saturated fat under 13 g a day; prefer oily fish, nuts and vegetable oils to butter and fatty meat"},
+		{"sodium", "mg", 1500, false, sodium, "keep sodium under 1500 mg a day"},
+	},
+	Stroke: {
+		{"sodium", "mg", 1500, false, sodium, "keep sodium under 1500 mg a day"},
+		{"saturatedFattyAcids", "g", 13, false, satFat, "keep saturated fat under 13 g a day"},
+	},
+}
+
+func (l limit) dailyLimit(weightKg float64) float64 {
+	if l.perKg {
+		return l.daily * weightKg
+	}
+	return l.daily
+}
+
+// Evaluate checks the totals of one meal against a share of each condition's
+// daily limits.
+func Evaluate(totals models.NutritionalData, conditions []Condition, weightKg float64) []models.HealthWarning {
+	warnings := []models.HealthWarning{}
+	for _, c := range conditions {
+		for _, l := range rules[c] {
+			got, ok := l.get(totals)
+			if !ok {
+				continue
+			}
+			max := l.dailyLimit(weightKg) * MealShare
+			if got <= max {
+				continue
+			}
+			warnings = append(warnings, models.HealthWarning{
+				Condition: string(c),
+				Nutrient:  l.nutrient,
+				Unit:      l.unit,
+				Amount:    round(got),
+				Limit:     round(max),
+				Message:   fmt.Sprintf("%s for one meal is %.0f%s, above the %.0f%s advised with %s", l.nutrient, got, l.unit, max, l.unit, c),
+			})
+		}
+	}
+	return warnings
+}
+
+// Constraints turns the conditions into plain sentences for the diet plan
+// prompt, one per nutrient with the strictest limit kept.
+func Constraints(conditions []Condition, weightKg float64) []string {
+	strictest := map[string]limit{}
+	for _, c := range conditions {
+		for _, l := range rules[c] {
+			if cur, ok := strictest[l.nutrient]; !ok || l.dailyLimit(weightKg) < cur.dailyLimit(weightKg) {
+				strictest[l.nutrient] = l
+			}
+		}
+	}
+
+	out := make([]string, 0, len(strictest))
+	for _, l := range strictest {
+		s := l.advice
+		if l.perKg {
+			s = fmt.Sprintf("%s (%.0f g)", s, l.dailyLimit(weightKg))
+		}
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// DailyLimit returns the strictest daily limit the conditions set for a
+// nutrient, e.g. "sodium" in mg.
+func DailyLimit(conditions []Condition, nutrient string, weightKg float64) (float64, bool) {
+	limit, found := 0.0, false
+	for _, c := range conditions {
+		for _, l := range rules[c] {
+			if l.nutrient != nutrient {
+				continue
+			}
+			if v := l.dailyLimit(weightKg); !found || v < limit {
+				limit, found = v, true
+			}
+		}
+	}
+	return limit, found
+}
+
+// AdjustTargets caps the protein and carbohydrate targets where a condition
+// limits them, so the targets agree with Constraints. Protein and carbohydrate
+// both carry 4 kcal/g, so capped protein moves to carbohydrate; carbohydrate
+// over its cap moves to fat at 9 kcal/g. The energy target is unchanged.
+func AdjustTargets(t models.EnergyTargets, conditions []Condition, weightKg float64) models.EnergyTargets {
+	if max, ok := DailyLimit(conditions, "protein", weightKg); ok {
+		if max = math.Floor(max); t.Macros.Protein > max {
+			t.Macros.Carbohydrate += t.Macros.Protein - max
+			t.Macros.Protein = max
+		}
+	}
+	if max, ok := DailyLimit(conditions, "carbohydrate", weightKg); ok && t.Macros.Carbohydrate > max {
+		t.Macros.Fat = round(t.Macros.Fat + (t.Macros.Carbohydrate-max)*4/9)
+		t.Macros.Carbohydrate = max
+	}
+	return t
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
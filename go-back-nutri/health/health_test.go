@@ -0,0 +1,105 @@
+package health
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MishraShardendu22/models"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse([]string{"diabetes", "hypertension", "diabetes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != Diabetes || got[1] != Hypertension {
+		t.Errorf("Expected diabetes and hypertension once each, got %v", got)
+	}
+
+	if _, err := Parse([]string{"flu"}); err == nil {
+		t.Error("Expected an error for an unknown condition")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	meal := models.NutritionalData{
+		Sodium:         900,
+		Sugars:         5,
+		Protein:        30,
+		Micronutrients: &models.Micronutrients{Potassium: 400},
+	}
+
+	// hypertension allows 500 mg sodium per meal, diabetes about 8.3 g sugars
+	got := Evaluate(meal, []Condition{Hypertension, Diabetes}, 70)
+	if len(got) != 1 || got[0].Nutrient != "sodium" || got[0].Limit != 500 {
+		t.Errorf("Expected a single sodium warning at 500 mg, got %+v", got)
+	}
+
+	// CKD at 60 kg allows 16 g protein and 666.7 mg potassium per meal
+	got = Evaluate(meal, []Condition{ChronicKidneyDisease}, 60)
+	nutrients := map[string]bool{}
+	for _, w := range got {
+		nutrients[w.Nutrient] = true
+	}
+	if !nutrients["protein"] || !nutrients["sodium"] || nutrients["potassium"] {
+		t.Errorf("Expected protein and sodium warnings only, got %+v", got)
+	}
+
+	if got := Evaluate(meal, []Condition{Asthma}, 70); len(got) != 0 {
+		t.Errorf("Expected no warnings for asthma, got %+v", got)
+	}
+}
+
+func TestEvaluateGlycaemicLoad(t *testing.T) {
+	gi := 70.0
+	// 50 g carbohydrate at GI 70 is a load of 35, above the 20 per meal
+	meal := models.NutritionalData{Carbohydrate: 50, GlycaemicIndex: &gi}
+	got := Evaluate(meal, []Condition{Diabetes}, 70)
+	if len(got) != 1 || got[0].Nutrient != "glycaemicLoad" || got[0].Limit != 20 {
+		t.Errorf("Expected a single glycaemic load warning at 20, got %+v", got)
+	}
+
+	// the same carbohydrate with no known GI only counts against the 60 g
+	meal.GlycaemicIndex = nil
+	if got := Evaluate(meal, []Condition{Diabetes}, 70); len(got) != 0 {
+		t.Errorf("Expected no warnings without a GI, got %+v", got)
+	}
+
+	meal.Carbohydrate = 90
+	got = Evaluate(meal, []Condition{Diabetes}, 70)
+	if len(got) != 1 || got[0].Nutrient != "carbohydrate" || got[0].Limit != 60 {
+		t.Errorf("Expected a single carbohydrate warning at 60 g, got %+v", got)
+	}
+}
+
+func TestConstraintsKeepStrictest(t *testing.T) {
+	got := Constraints([]Condition{ChronicKidneyDisease, Hypertension}, 70)
+	sodium := 0
+	for _, s := range got {
+		if strings.HasPrefix(s, "keep sodium under 1500 mg") {
+			sodium++
+		}
+		if s == "keep sodium under 2000 mg a day" {
+			t.Error("Expected the looser CKD sodium limit to be dropped")
+		}
+	}
+	if sodium != 1 || len(got) != 3 {
+		t.Errorf("Expected sodium, potassium and protein constraints, got %q", got)
+	}
+}
+
+func TestAdjustTargets(t *testing.T) {
+	in := models.EnergyTargets{Macros: models.MacroTargets{EnergyKcal: 2000, Protein: 100, Carbohydrate: 250}}
+	got := AdjustTargets(in, []Condition{ChronicKidneyDisease}, 70)
+	if got.Macros.Protein != 56 || got.Macros.Carbohydrate != 294 || got.Macros.EnergyKcal != 2000 {
+		t.Errorf("Expected protein capped at 56 g with carbohydrate making up the energy, got %+v", got.Macros)
+	}
+
+	// the 44 g of protein moved to carbohydrate goes over the diabetes cap, so
+	// the 114 g above 180 g moves on to fat
+	in.Macros.Fat = 67
+	got = AdjustTargets(in, []Condition{Diabetes, ChronicKidneyDisease}, 70)
+	if got.Macros.Protein != 56 || got.Macros.Carbohydrate != 180 || got.Macros.Fat != 117.7 || got.Macros.EnergyKcal != 2000 {
+		t.Errorf("Expected 56 g protein, 180 g carbohydrate and 117.7 g fat, got %+v", got.Macros)
+	}
+}
@@ -1,24 +1,50 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/MishraShardendu22/cache"
 	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/energy"
+	"github.com/MishraShardendu22/estimate"
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/health"
+	"github.com/MishraShardendu22/meallog"
 	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/off"
+	"github.com/MishraShardendu22/provider"
 	"github.com/MishraShardendu22/score"
 	"github.com/MishraShardendu22/util"
-	"github.com/go-resty/resty/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/joho/godotenv"
 )
 
-var OpenAI_KEY string
-var server_url = "https://nutrition-calculator-server.onrender.com"
+var LLMProvider provider.Provider
+var FoodIndex *foods.Index
+
+// scored meals per user; bbolt at MEAL_LOG_PATH, in memory otherwise
+var MealLog meallog.Store
+
+// packaged products by barcode, from OFF_DUMP and/or OFF_URL
+var ProductSource off.Source
+
+// upper bound for a whole /api/food call, retries included
+var RequestTimeout = 2 * time.Minute
+
+// optional remote deployment of this service that scores the meal for
+// /api/food; it must serve /api/calculate-nutrition/explain. Scoring runs
+// in-process unless this is set
+var CalculatorURL string
 
 func main() {
 	if os.Getenv("ENVIRONMENT") == "DEVELOPMENT" {
@@ -27,10 +53,80 @@ func main() {
 		}
 	}
 
-	OpenAI_KEY = os.Getenv("OPENAI_KEY")
+	timeout, err := time.ParseDuration(os.Getenv("LLM_TIMEOUT"))
+	if err != nil {
+		timeout = provider.DefaultTimeout
+	}
+
+	// LLM_PROVIDER is openrouter (default), openai for any OpenAI-compatible
+	// base URL such as a local llama.cpp or Ollama server, or fake for offline use
+	retries, err := strconv.Atoi(os.Getenv("LLM_RETRIES"))
+	if err != nil {
+		retries = provider.DefaultRetries
+	}
+
+	if d, err := time.ParseDuration(os.Getenv("REQUEST_TIMEOUT")); err == nil {
+		RequestTimeout = d
+	}
+
+	LLMProvider, err = provider.New(provider.Config{
+		Kind:    os.Getenv("LLM_PROVIDER"),
+		BaseURL: os.Getenv("LLM_BASE_URL"),
+		APIKey:  os.Getenv("OPENAI_KEY"),
+		Model:   os.Getenv("LLM_MODEL"),
+		Timeout: timeout,
+		Retries: retries,
+	})
+	if err != nil {
+		log.Fatal("llm provider: ", err)
+	}
+
+	CalculatorURL = os.Getenv("CALCULATOR_URL")
+	AdminToken = os.Getenv("ADMIN_TOKEN")
+	JWTSecret = os.Getenv("JWT_SECRET_KEY")
+
+	// CACHE_BACKEND is memory (default), redis with REDIS_URL, or none
+	cacheTTL, err := time.ParseDuration(os.Getenv("CACHE_TTL"))
+	if err != nil {
+		cacheTTL = cache.DefaultTTL
+	}
+	switch os.Getenv("CACHE_BACKEND") {
+	case "", "memory":
+		size, _ := strconv.Atoi(os.Getenv("CACHE_SIZE"))
+		util.LLMCache = cache.New(cache.NewLRU(size), cacheTTL)
+	case "redis":
+		backend, err := cache.NewRedis(os.Getenv("REDIS_URL"))
+		if err != nil {
+			log.Fatal("cache: ", err)
+		}
+		util.LLMCache = cache.New(backend, cacheTTL)
+	case "none":
+	default:
+		log.Fatal("unknown cache backend ", os.Getenv("CACHE_BACKEND"))
+	}
+
+	FoodIndex, err = foods.Embedded()
+	if err != nil {
+		log.Fatal("food table: ", err)
+	}
 
-	if OpenAI_KEY == "" {
-		log.Fatal("OPENAI_KEY not set")
+	if path := os.Getenv("OFF_DUMP"); path != "" {
+		if ProductSource.Index, err = off.LoadFile(path); err != nil {
+			log.Fatal("product dump: ", err)
+		}
+		log.Printf("loaded %d products from %s", ProductSource.Index.Len(), path)
+	}
+	if url := os.Getenv("OFF_URL"); url != "" {
+		ProductSource.Mirror = off.NewMirror(url, off.DefaultTimeout)
+	}
+
+	if path := os.Getenv("MEAL_LOG_PATH"); path != "" {
+		if MealLog, err = meallog.OpenBolt(path); err != nil {
+			log.Fatal("meal log: ", err)
+		}
+	} else {
+		log.Print("MEAL_LOG_PATH not set, meal log is kept in memory")
+		MealLog = meallog.NewMemory()
 	}
 
 	app := fiber.New()
@@ -38,143 +134,369 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:  "*",
 		AllowMethods:  "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:  "Origin, Content-Type, Accept",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization",
 		ExposeHeaders: "Content-Length",
 	}))
 
 	app.Get("/test123", test)
 	app.Post("/api/food", food)
+	app.Post("/api/food/stream", foodStream)
 	app.Post("/api/calculate-nutrition", calc)
+	app.Post("/api/calculate-nutrition/versions", compareVersions)
+	app.Post("/api/calculate-nutrition/explain", explain)
+	app.Post("/api/calculate-nutrition/batch", batchScore)
+	app.Post("/api/rate", rate)
+	app.Post("/api/recipe", recipeScore)
+	app.Post("/api/energy", energyTargets)
+	app.Post("/api/plan", dayPlan)
+	app.Post("/api/micronutrients", micronutrients)
+	app.Get("/api/product/:barcode", product)
+	// a user's log is only open to a token issued for that user
+	app.Post("/api/log/:user", ownLog, addLog)
+	app.Get("/api/log/:user", ownLog, listLog)
+	app.Get("/api/log/:user/daily", ownLog, dailyLog)
+	app.Get("/api/log/:user/weekly", ownLog, weeklyLog)
+
+	admin := app.Group("/api/admin", adminOnly)
+	admin.Get("/cache/stats", cacheStats)
+	admin.Delete("/cache", invalidateCache)
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "3004"
 	}
 
-	log.Fatal(app.Listen(":" + port))
+	// finish in-flight requests on SIGINT/SIGTERM so the meal log is closed
+	// cleanly; log.Fatal would skip a deferred Close
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		if err := app.Shutdown(); err != nil {
+			log.Print("shutdown: ", err)
+		}
+	}()
+
+	err = app.Listen(":" + port)
+	if cerr := MealLog.Close(); cerr != nil {
+		log.Print("meal log: ", cerr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 
-func calc(c *fiber.Ctx) error {
-	var data models.NutritionalData
-	if err := c.BodyParser(&data); err != nil {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid request data", nil, "")
+type calcRequest struct {
+	models.NutritionalData
+	ScoreType string
+	Version   string
+}
+
+type versionResult struct {
+	Version models.Version          `json:"version"`
+	Score   models.NutritionalScore `json:"score"`
+	Grade   string                  `json:"grade"`
+}
+
+func parseCalcRequest(c *fiber.Ctx) (calcRequest, models.ScoreType, models.Version, error) {
+	var req calcRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, models.Auto, models.V2017, fiber.NewError(fiber.StatusBadRequest, "invalid request data")
 	}
+	st, v, err := cal.Validate(req.NutritionalData, req.ScoreType, req.Version, models.V2017)
+	if err != nil {
+		return req, st, v, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return req, st, v, nil
+}
 
-	if data.Fibre < 0 || data.Energy < 0 || data.Protein < 0 || data.Sugars < 0 ||
-		data.Fruits < 0 || data.Sodium < 0 || data.SaturatedFattyAcids < 0 {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "required value is missing", nil, "")
+func calc(c *fiber.Ctx) error {
+	req, st, v, err := parseCalcRequest(c)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
 	}
 
-	ns := cal.Calculate(data, models.Food)
-	grade := score.GetGrade(int(ns.Value))
+	ns := cal.Calculate(req.NutritionalData, st, v)
+	grade := score.GetGrade(ns.Value, ns.ScoreType, ns.Version)
 
 	return util.ResponseAPI(c, fiber.StatusAccepted, "nutrition score calculated successfully", grade, "")
 }
 
+func explain(c *fiber.Ctx) error {
+	req, st, v, err := parseCalcRequest(c)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	return util.ResponseAPI(c, fiber.StatusOK, "nutrition score explained successfully", cal.Explain(req.NutritionalData, st, v), "")
+}
+
+// compareVersions scores the same product under every algorithm version so
+// callers can see how its grade moves between them.
+func compareVersions(c *fiber.Ctx) error {
+	req, st, _, err := parseCalcRequest(c)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	results := make([]versionResult, 0, len(models.Versions))
+	for _, v := range models.Versions {
+		ns := cal.Calculate(req.NutritionalData, st, v)
+		results = append(results, versionResult{
+			Version: v,
+			Score:   ns,
+			Grade:   score.GetGrade(ns.Value, ns.ScoreType, ns.Version),
+		})
+	}
+
+	return util.ResponseAPI(c, fiber.StatusOK, "nutrition score calculated for every version", results, "")
+}
+
 func test(c *fiber.Ctx) error {
 	return util.ResponseAPI(c, fiber.StatusOK, "application is working", nil, "")
 }
 
-func food(c *fiber.Ctx) error {
-	var payload map[string]string
-	if err := c.BodyParser(&payload); err != nil {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid request data", nil, "")
+type foodRequest struct {
+	Diet       string   `json:"diet"`
+	Weight     string   `json:"weight"`
+	Height     string   `json:"height"`
+	Gender     string   `json:"gender"`
+	BloodGroup string   `json:"bloodGroup"`
+	Version    string   `json:"version"`
+	Age        string   `json:"age"`
+	Activity   string   `json:"activity"`
+	Goal       string   `json:"goal"`
+	Conditions []string `json:"conditions"`
+	// dietary patterns (vegetarian, vegan, jain, ...) and allergens the
+	// generated plan must respect
+	Patterns  []string `json:"patterns"`
+	Allergens []string `json:"allergens"`
+	// when set, the scored meal is added to this user's log; the request must
+	// carry that user's bearer token
+	UserID string `json:"userId"`
+}
+
+// foodInput is a validated /api/food payload, shared with /api/food/stream.
+type foodInput struct {
+	foodRequest
+	height, weight int
+	version        models.Version
+	conditions     []health.Condition
+	restrictions   foods.Restrictions
+	targets        models.EnergyTargets
+}
+
+func parseFoodRequest(c *fiber.Ctx) (foodInput, error) {
+	var in foodInput
+	if err := c.BodyParser(&in.foodRequest); err != nil {
+		return in, fiber.NewError(fiber.StatusBadRequest, "invalid request data")
 	}
 
-	diet, ok := payload["diet"]
-	if !ok || diet == "" {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "diet cannot be empty", nil, "")
+	switch {
+	case in.Diet == "":
+		return in, fiber.NewError(fiber.StatusBadRequest, "diet cannot be empty")
+	case in.Weight == "":
+		return in, fiber.NewError(fiber.StatusBadRequest, "weight cannot be empty")
+	case in.Height == "":
+		return in, fiber.NewError(fiber.StatusBadRequest, "height cannot be empty")
+	case in.Gender == "":
+		return in, fiber.NewError(fiber.StatusBadRequest, "gender cannot be empty")
+	case in.BloodGroup == "":
+		return in, fiber.NewError(fiber.StatusBadRequest, "blood group cannot be empty")
 	}
 
-	weight, ok := payload["weight"]
-	if !ok || weight == "" {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "weight cannot be empty", nil, "")
+	var err error
+	if in.height, err = strconv.Atoi(in.Height); err != nil {
+		return in, fiber.NewError(fiber.StatusBadRequest, "invalid height value")
+	}
+	if in.weight, err = strconv.Atoi(in.Weight); err != nil {
+		return in, fiber.NewError(fiber.StatusBadRequest, "invalid weight value")
 	}
 
-	height, ok := payload["height"]
-	if !ok || height == "" {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "height cannot be empty", nil, "")
+	var ok bool
+	if in.version, ok = models.ParseVersion(in.Version); !ok {
+		return in, fiber.NewError(fiber.StatusBadRequest, "invalid algorithm version")
 	}
 
-	gender, ok := payload["gender"]
-	if !ok || gender == "" {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "gender cannot be empty", nil, "")
+	ageInt := 0
+	if in.Age != "" {
+		if ageInt, err = strconv.Atoi(in.Age); err != nil {
+			return in, fiber.NewError(fiber.StatusBadRequest, "invalid age value")
+		}
 	}
 
-	bloodGroup, ok := payload["bloodGroup"]
-	if !ok || bloodGroup == "" {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "blood group cannot be empty", nil, "")
+	energyIn := energy.Input{
+		HeightCm: float64(in.height),
+		WeightKg: float64(in.weight),
+		Age:      ageInt,
+		Gender:   in.Gender,
+		Activity: in.Activity,
+		Goal:     in.Goal,
 	}
+	if err := energyIn.Validate(); err != nil {
+		return in, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if in.conditions, err = health.Parse(in.Conditions); err != nil {
+		return in, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if in.restrictions, err = foods.ParseRestrictions(in.Patterns, in.Allergens); err != nil {
+		return in, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	in.targets = health.AdjustTargets(energy.Calculate(energyIn), in.conditions, float64(in.weight))
+
+	if in.UserID != "" {
+		if err := authorise(c, in.UserID); err != nil {
+			return in, err
+		}
+	}
+
+	return in, nil
+}
 
-	heightInt, err := strconv.Atoi(height)
+func (in foodInput) planRequest() models.PlanRequest {
+	return models.PlanRequest{
+		Height:      in.height,
+		Weight:      in.weight,
+		BloodGroup:  in.BloodGroup,
+		Gender:      in.Gender,
+		Targets:     &in.targets,
+		Conditions:  in.Conditions,
+		Constraints: health.Constraints(in.conditions, float64(in.weight)),
+		Patterns:    in.restrictions.Patterns,
+		Allergens:   in.restrictions.Allergens,
+	}
+}
+
+// scoreDiet estimates and grades the meal, deferring to CalculatorURL for
+// the meal's score and grade when it is set.
+func scoreDiet(ctx context.Context, in foodInput) (models.MealScore, error) {
+	items, err := estimate.Items(ctx, LLMProvider, FoodIndex, in.Diet)
 	if err != nil {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid height value", nil, "")
+		return models.MealScore{}, err
+	}
+
+	meal := cal.Combine(items)
+	ms := cal.ScoreMeal(meal, in.version)
+
+	if CalculatorURL != "" {
+		ns, grade, err := util.RemoteScore(ctx, CalculatorURL, meal.Per100g, in.version)
+		if err != nil {
+			return ms, err
+		}
+		ms.Score, ms.Grade = ns, grade
 	}
-	weightInt, err := strconv.Atoi(weight)
+
+	return ms, nil
+}
+
+// logMeal adds the scored meal to the user's log when the request names one.
+func logMeal(ctx context.Context, in foodInput, ms models.MealScore) {
+	if in.UserID == "" {
+		return
+	}
+	if _, err := MealLog.Add(ctx, newLogEntry(in.UserID, in.Diet, ms, time.Now())); err != nil {
+		log.Print("meal log: ", err)
+	}
+}
+
+func food(c *fiber.Ctx) error {
+	in, err := parseFoodRequest(c)
 	if err != nil {
-		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid weight value", nil, "")
+		return util.ResponseAPI(c, errorStatus(err), err.Error(), nil, "")
 	}
 
-	type result struct {
-		val string
+	type ttResult struct {
+		val models.DietPlan
 		err error
 	}
 
-	nutriCh := make(chan result)
-	ttCh := make(chan result)
+	type nutriResult struct {
+		val models.MealScore
+		err error
+	}
 
-	go func() {
-		parsed := util.LLM(diet, OpenAI_KEY)
-		parsedJSON, _ := json.Marshal(parsed)
+	type foodResponse struct {
+		models.MealScore
+		Targets        models.EnergyTargets   `json:"targets"`
+		HealthWarnings []models.HealthWarning `json:"healthWarnings"`
+		Plan           models.DietPlan        `json:"plan"`
+		Timetable      string                 `json:"timetable"`
+	}
 
-		client := resty.New()
-		res, err := client.R().
-			SetHeader("Content-Type", "application/json").
-			SetBody(parsedJSON).
-			Post(server_url + "/api/calculate-nutrition")
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
 
-		if err != nil {
-			nutriCh <- result{"", err}
-			return
-		}
-		if res.IsError() {
-			nutriCh <- result{"", fiber.NewError(fiber.StatusInternalServerError, "error response from nutrition calculator")}
-			return
-		}
+	// buffered so neither goroutine blocks forever when we return early
+	nutriCh := make(chan nutriResult, 1)
+	ttCh := make(chan ttResult, 1)
 
-		nutriCh <- result{res.String(), nil}
+	go func() {
+		ms, err := scoreDiet(ctx, in)
+		nutriCh <- nutriResult{ms, err}
 	}()
 
 	go func() {
-		tt, err := util.TT(heightInt, weightInt, bloodGroup, gender, OpenAI_KEY)
-		if err != nil {
-			ttCh <- result{"", err}
-			return
-		}
-		
-		fmt.Println("Timetable response:", tt)
-
-		ttStr, _ := tt.(string)
-		ttCh <- result{ttStr, nil}
+		tt, err := util.TT(ctx, LLMProvider, in.planRequest(), in.checkPlan)
+		ttCh <- ttResult{tt, err}
 	}()
 
 	nutriRes := <-nutriCh
 	if nutriRes.err != nil {
-		return util.ResponseAPI(c, fiber.StatusInternalServerError, nutriRes.err.Error(), nil, "")
-	}
-
-	var parsed map[string]interface{}
-	if err := json.Unmarshal([]byte(nutriRes.val), &parsed); err != nil {
-		return util.ResponseAPI(c, fiber.StatusInternalServerError, "invalid JSON in response", nil, "")
+		return util.ResponseAPI(c, llmErrorStatus(nutriRes.err), nutriRes.err.Error(), nil, "")
 	}
 
-	grade, _ := parsed["data"].(string)
-
 	ttRes := <-ttCh
 	if ttRes.err != nil {
-		return util.ResponseAPI(c, fiber.StatusInternalServerError, "failed to generate timetable", nil, "")
+		return util.ResponseAPI(c, llmErrorStatus(ttRes.err), planErrorMessage(ttRes.err), nil, "")
 	}
 
-	final := []string{grade, ttRes.val}
+	logMeal(ctx, in, nutriRes.val)
+
+	final := foodResponse{
+		MealScore:      nutriRes.val,
+		Targets:        in.targets,
+		HealthWarnings: health.Evaluate(nutriRes.val.Totals, in.conditions, float64(in.weight)),
+		Plan:           ttRes.val,
+		Timetable:      ttRes.val.Text(),
+	}
 	return util.ResponseAPI(c, fiber.StatusOK, "food data processed successfully", final, "")
-}
\ No newline at end of file
+}
+
+// checkPlan rejects generated plans with items the request's patterns or
+// allergens rule out, judged by the food table and the dish names.
+func (in foodInput) checkPlan(plan models.DietPlan) error {
+	violations := foods.CheckPlan(FoodIndex, plan, in.restrictions)
+	if len(violations) == 0 {
+		return nil
+	}
+	reasons := make([]string, len(violations))
+	for i, v := range violations {
+		reasons[i] = v.String()
+	}
+	return fmt.Errorf("%w: %s", util.ErrRestricted, strings.Join(reasons, "; "))
+}
+
+// planErrorMessage names the broken restrictions so the client can show them;
+// other plan failures stay generic.
+func planErrorMessage(err error) string {
+	if errors.Is(err, util.ErrRestricted) {
+		return err.Error()
+	}
+	return "failed to generate timetable"
+}
+
+func llmErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, provider.ErrRateLimited):
+		return fiber.StatusTooManyRequests
+	case errors.Is(err, provider.ErrUnavailable):
+		return fiber.StatusServiceUnavailable
+	case errors.Is(err, provider.ErrUnparseable), errors.Is(err, util.ErrRestricted):
+		return fiber.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/provider"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestFoodWithFakeProviderKeepsRestrictions(t *testing.T) {
+	defer func(p provider.Provider, ix *foods.Index, url string) {
+		LLMProvider, FoodIndex, CalculatorURL = p, ix, url
+	}(LLMProvider, FoodIndex, CalculatorURL)
+
+	ix, err := foods.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	LLMProvider, FoodIndex, CalculatorURL = provider.NewFake(), ix, ""
+
+	app := fiber.New()
+	app.Post("/api/food", food)
+
+	cases := []struct {
+		patterns  []string
+		allergens []string
+	}{
+		{[]string{foods.Vegan}, nil},
+		{[]string{foods.Jain}, nil},
+		{[]string{foods.GlutenFree, foods.LactoseFree}, []string{"milk", "gluten"}},
+	}
+	for _, tc := range cases {
+		body, _ := json.Marshal(foodRequest{
+			Diet:       "rice, dal",
+			Weight:     "70",
+			Height:     "170",
+			Gender:     "male",
+			BloodGroup: "O+",
+			Patterns:   tc.patterns,
+			Allergens:  tc.allergens,
+		})
+		req := httptest.NewRequest("POST", "/api/food", bytes.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		res, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != fiber.StatusOK {
+			var out struct {
+				Message string `json:"message"`
+			}
+			json.NewDecoder(res.Body).Decode(&out)
+			t.Errorf("Expected status 200 for %v %v, got %d: %s", tc.patterns, tc.allergens, res.StatusCode, out.Message)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/estimate"
+	"github.com/MishraShardendu22/meallog"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type logRequest struct {
+	Diet    string `json:"diet"`
+	Version string `json:"version"`
+	// RFC 3339; defaults to now
+	Time string `json:"time"`
+}
+
+func newLogEntry(userID, diet string, ms models.MealScore, at time.Time) models.LogEntry {
+	items := make([]models.FoodItem, 0, len(ms.Items))
+	for _, it := range ms.Items {
+		items = append(items, models.FoodItem{Name: it.Name, Grams: it.Grams, Data: it.Data, Source: it.Source})
+	}
+	return models.LogEntry{
+		UserID:  userID,
+		Time:    at,
+		Diet:    diet,
+		Version: ms.Score.Version,
+		Items:   items,
+		Grams:   ms.Grams,
+		Totals:  ms.Totals,
+		Score:   ms.Score.Value,
+		Grade:   ms.Grade,
+	}
+}
+
+// addLog scores a meal the same way /api/food does and stores it.
+func addLog(c *fiber.Ctx) error {
+	var req logRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid request data", nil, "")
+	}
+	if strings.TrimSpace(req.Diet) == "" {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "diet cannot be empty", nil, "")
+	}
+	version, ok := models.ParseVersion(req.Version)
+	if !ok {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid algorithm version", nil, "")
+	}
+	at := time.Now()
+	if req.Time != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, req.Time); err != nil {
+			return util.ResponseAPI(c, fiber.StatusBadRequest, "time must be RFC 3339", nil, "")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	items, err := estimate.Items(ctx, LLMProvider, FoodIndex, req.Diet)
+	if err != nil {
+		return util.ResponseAPI(c, llmErrorStatus(err), err.Error(), nil, "")
+	}
+	ms := cal.ScoreMeal(cal.Combine(items), version)
+
+	entry, err := MealLog.Add(ctx, newLogEntry(c.Params("user"), req.Diet, ms, at))
+	if errors.Is(err, meallog.ErrInvalidEntry) {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusInternalServerError, "failed to store meal", nil, "")
+	}
+	return util.ResponseAPI(c, fiber.StatusCreated, "meal logged successfully", entry, "")
+}
+
+// logQuery reads the shared query parameters: tz (IANA name, default UTC) and
+// the daily energyKcal, sodiumMax and sugarsMax targets.
+func logQuery(c *fiber.Ctx) (*time.Location, models.LogTargets, error) {
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, models.LogTargets{}, errors.New("invalid time zone")
+		}
+	}
+
+	t := models.LogTargets{
+		EnergyKcal: c.QueryFloat("energyKcal"),
+		SodiumMax:  c.QueryFloat("sodiumMax"),
+		SugarsMax:  c.QueryFloat("sugarsMax"),
+	}
+	if t.EnergyKcal < 0 || t.SodiumMax < 0 || t.SugarsMax < 0 {
+		return nil, t, errors.New("targets cannot be negative")
+	}
+	return loc, meallog.WithDefaults(t), nil
+}
+
+// queryDate parses a YYYY-MM-DD query parameter in loc, defaulting to today.
+func queryDate(c *fiber.Ctx, key string, loc *time.Location) (time.Time, error) {
+	v := c.Query(key)
+	if v == "" {
+		return time.Now().In(loc), nil
+	}
+	d, err := time.ParseInLocation(meallog.DateLayout, v, loc)
+	if err != nil {
+		return d, errors.New(key + " must be YYYY-MM-DD")
+	}
+	return d, nil
+}
+
+// listLog returns raw entries between ?from= and ?to= (inclusive dates).
+func listLog(c *fiber.Ctx) error {
+	loc, _, err := logQuery(c)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+	to, err := queryDate(c, "to", loc)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+	from := to.AddDate(0, 0, -6)
+	if c.Query("from") != "" {
+		if from, err = queryDate(c, "from", loc); err != nil {
+			return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	entries, err := MealLog.List(ctx, c.Params("user"), start, end)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusInternalServerError, "failed to read meal log", nil, "")
+	}
+	if entries == nil {
+		entries = []models.LogEntry{}
+	}
+	return util.ResponseAPI(c, fiber.StatusOK, "meal log fetched successfully", entries, "")
+}
+
+func dailyLog(c *fiber.Ctx) error {
+	loc, targets, err := logQuery(c)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+	date, err := queryDate(c, "date", loc)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	days, err := meallog.Days(ctx, MealLog, c.Params("user"), date, date, loc, targets)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusInternalServerError, "failed to read meal log", nil, "")
+	}
+	return util.ResponseAPI(c, fiber.StatusOK, "daily summary calculated successfully", days[0], "")
+}
+
+func weeklyLog(c *fiber.Ctx) error {
+	loc, targets, err := logQuery(c)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+	end, err := queryDate(c, "end", loc)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	week, err := meallog.Week(ctx, MealLog, c.Params("user"), end, loc, targets)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusInternalServerError, "failed to read meal log", nil, "")
+	}
+	return util.ResponseAPI(c, fiber.StatusOK, "weekly summary calculated successfully", week, "")
+}
@@ -0,0 +1,77 @@
+package meallog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/MishraShardendu22/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Bolt keeps the log in a single bbolt file: one bucket per user, keyed by
+// the entry time so a date range is a cursor seek.
+type Bolt struct {
+	db *bolt.DB
+}
+
+// key layout sorts by time: RFC 3339 with fixed nanoseconds, then the ID
+const keyTime = "2006-01-02T15:04:05.000000000Z"
+
+func OpenBolt(path string) (*Bolt, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open meal log: %w", err)
+	}
+	return &Bolt{db: db}, nil
+}
+
+func (b *Bolt) Add(ctx context.Context, e models.LogEntry) (models.LogEntry, error) {
+	e, err := prepare(e)
+	if err != nil {
+		return e, err
+	}
+
+	val, err := json.Marshal(e)
+	if err != nil {
+		return e, fmt.Errorf("marshal log entry: %w", err)
+	}
+
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(e.UserID))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(e.Time.Format(keyTime)+"/"+e.ID), val)
+	})
+	return e, err
+}
+
+func (b *Bolt) List(ctx context.Context, userID string, from, to time.Time) ([]models.LogEntry, error) {
+	var out []models.LogEntry
+	min := []byte(from.UTC().Format(keyTime))
+	max := []byte(to.UTC().Format(keyTime))
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(userID))
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Seek(min); k != nil && bytes.Compare(k, max) < 0; k, v = c.Next() {
+			var e models.LogEntry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return fmt.Errorf("unmarshal log entry %s: %w", k, err)
+			}
+			out = append(out, e)
+		}
+		return ctx.Err()
+	})
+	return out, err
+}
+
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,141 @@
+package meallog
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MishraShardendu22/models"
+)
+
+func entry(user string, at time.Time, kcal, sodium, sugars float64, grade string) models.LogEntry {
+	return models.LogEntry{
+		UserID: user,
+		Time:   at,
+		Grams:  300,
+		Grade:  grade,
+		Totals: models.NutritionalData{
+			Energy: models.EnergyKJ(kcal * 4.184),
+			Sodium: models.SodiumMilligram(sodium),
+			Sugars: models.SugarGram(sugars),
+		},
+	}
+}
+
+func stores(t *testing.T) map[string]Store {
+	b, err := OpenBolt(filepath.Join(t.TempDir(), "log.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return map[string]Store{"memory": NewMemory(), "bolt": b}
+}
+
+func TestStoreListsByRange(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	for name, s := range stores(t) {
+		for _, at := range []time.Time{day.Add(20 * time.Hour), day.Add(8 * time.Hour), day.AddDate(0, 0, 1)} {
+			if _, err := s.Add(ctx, entry("u1", at, 500, 300, 10, "B")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		s.Add(ctx, entry("u2", day.Add(9*time.Hour), 500, 300, 10, "A"))
+
+		got, err := s.List(ctx, "u1", day, day.AddDate(0, 0, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].Time.Hour() != 8 || got[1].Time.Hour() != 20 || got[0].ID == "" {
+			t.Errorf("%s: expected the two entries of the day oldest first, got %+v", name, got)
+		}
+
+		if _, err := s.Add(ctx, entry("", day, 0, 0, 0, "A")); err == nil {
+			t.Errorf("%s: expected an error without a user id", name)
+		}
+	}
+}
+
+func TestWeekAndStreak(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory()
+	end := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	targets := WithDefaults(models.LogTargets{EnergyKcal: 2000})
+
+	// 10 days meeting the targets, except 4 days ago when sodium went over
+	for i := 0; i < 10; i++ {
+		d := end.AddDate(0, 0, -i)
+		sodium := 600.0
+		if i == 4 {
+			sodium = 1200
+		}
+		s.Add(ctx, entry("u1", d.Add(8*time.Hour), 600, sodium, 10, "A"))
+		s.Add(ctx, entry("u1", d.Add(13*time.Hour), 800, sodium, 15, "C"))
+		s.Add(ctx, entry("u1", d.Add(20*time.Hour), 600, sodium, 10, "B"))
+	}
+
+	week, err := Week(ctx, s, "u1", end, time.UTC, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(week.Days) != 7 || week.From != "2025-03-04" || week.To != "2025-03-10" {
+		t.Fatalf("Expected 7 days ending 2025-03-10, got %s to %s with %d days", week.From, week.To, len(week.Days))
+	}
+	today := week.Days[6]
+	if today.Entries != 3 || today.EnergyKcal != 2000 || today.Sugars != 35 || today.AverageGrade != "B" || !today.Met {
+		t.Errorf("Expected a met day at 2000 kcal graded B, got %+v", today)
+	}
+	if week.DaysMet != 6 || week.Streak != 4 {
+		t.Errorf("Expected 6 days met and a streak of 4, got %d and %d", week.DaysMet, week.Streak)
+	}
+
+	empty, _ := Days(ctx, s, "u1", end.AddDate(0, 0, 1), end.AddDate(0, 0, 1), time.UTC, targets)
+	if empty[0].Met || empty[0].Entries != 0 {
+		t.Errorf("Expected a day without entries not to count as met, got %+v", empty[0])
+	}
+}
+
+func TestStreakWaitsForTodaysFirstMeal(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory()
+	yesterday := time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)
+	targets := WithDefaults(models.LogTargets{EnergyKcal: 2000})
+
+	for i := 0; i < 3; i++ {
+		d := yesterday.AddDate(0, 0, -i)
+		s.Add(ctx, entry("u1", d.Add(8*time.Hour), 1000, 600, 10, "A"))
+		s.Add(ctx, entry("u1", d.Add(20*time.Hour), 1000, 600, 10, "A"))
+	}
+
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2025, 3, 10, 7, 0, 0, 0, time.UTC) }
+
+	streak, err := Streak(ctx, s, "u1", now(), time.UTC, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streak != 3 {
+		t.Errorf("Expected the streak to carry on from yesterday, got %d", streak)
+	}
+
+	// any other day without entries still breaks it
+	if streak, _ := Streak(ctx, s, "u1", now().AddDate(0, 0, 1), time.UTC, targets); streak != 0 {
+		t.Errorf("Expected a missed day to break the streak, got %d", streak)
+	}
+}
+
+func TestDaysUseTimeZone(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemory()
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+
+	// 20:00 UTC on the 9th is 01:30 on the 10th in India
+	s.Add(ctx, entry("u1", time.Date(2025, 3, 9, 20, 0, 0, 0, time.UTC), 500, 100, 5, "A"))
+
+	days, _ := Days(ctx, s, "u1", time.Date(2025, 3, 10, 12, 0, 0, 0, kolkata), time.Date(2025, 3, 10, 12, 0, 0, 0, kolkata), kolkata, WithDefaults(models.LogTargets{}))
+	if days[0].Date != "2025-03-10" || days[0].Entries != 1 {
+		t.Errorf("Expected the entry on the local 10th, got %+v", days[0])
+	}
+}
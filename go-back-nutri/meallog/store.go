@@ -0,0 +1,82 @@
+package meallog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MishraShardendu22/models"
+)
+
+var ErrInvalidEntry = errors.New("invalid log entry")
+
+// Store keeps scored meals per user. List returns a user's entries with from
+// <= Time < to, oldest first.
+type Store interface {
+	Add(ctx context.Context, e models.LogEntry) (models.LogEntry, error)
+	List(ctx context.Context, userID string, from, to time.Time) ([]models.LogEntry, error)
+	Close() error
+}
+
+// prepare fills in the ID and time and checks what every store needs.
+func prepare(e models.LogEntry) (models.LogEntry, error) {
+	if e.UserID == "" {
+		return e, errors.Join(ErrInvalidEntry, errors.New("user id is required"))
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Time = e.Time.UTC()
+	if e.ID == "" {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return e, err
+		}
+		e.ID = hex.EncodeToString(b)
+	}
+	return e, nil
+}
+
+// Memory is a Store for tests and deployments that don't need the log to
+// survive a restart.
+type Memory struct {
+	mu      sync.RWMutex
+	entries map[string][]models.LogEntry
+}
+
+func NewMemory() *Memory {
+	return &Memory{entries: map[string][]models.LogEntry{}}
+}
+
+func (m *Memory) Add(ctx context.Context, e models.LogEntry) (models.LogEntry, error) {
+	e, err := prepare(e)
+	if err != nil {
+		return e, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := append(m.entries[e.UserID], e)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Time.Before(list[j].Time) })
+	m.entries[e.UserID] = list
+	return e, nil
+}
+
+func (m *Memory) List(ctx context.Context, userID string, from, to time.Time) ([]models.LogEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var out []models.LogEntry
+	for _, e := range m.entries[userID] {
+		if !e.Time.Before(from) && e.Time.Before(to) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (m *Memory) Close() error { return nil }
@@ -0,0 +1,177 @@
+package meallog
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/MishraShardendu22/constant"
+	"github.com/MishraShardendu22/models"
+)
+
+const DateLayout = "2006-01-02"
+
+// WHO daily limits used when the caller sets none: 2 g sodium and 10% of a
+// 2000 kcal diet as sugars
+const (
+	DefaultSodiumMax = 2000
+	DefaultSugarsMax = 50
+)
+
+// energy counts as met within this share of the target
+const EnergyTolerance = 0.10
+
+// streaks are counted back at most this many days
+const MaxStreak = 365
+
+// now is swapped in tests
+var now = time.Now
+
+// WithDefaults fills unset limits.
+func WithDefaults(t models.LogTargets) models.LogTargets {
+	if t.SodiumMax <= 0 {
+		t.SodiumMax = DefaultSodiumMax
+	}
+	if t.SugarsMax <= 0 {
+		t.SugarsMax = DefaultSugarsMax
+	}
+	return t
+}
+
+// Day sums the entries logged on one date.
+func Day(date string, entries []models.LogEntry, t models.LogTargets) models.DaySummary {
+	d := models.DaySummary{Date: date, Entries: len(entries)}
+	var gradeWeighted, grams float64
+
+	for _, e := range entries {
+		d.EnergyKcal += float64(e.Totals.Energy) / 4.184
+		d.Protein += float64(e.Totals.Protein)
+		d.Fibre += float64(e.Totals.Fibre)
+		d.Sugars += float64(e.Totals.Sugars)
+		d.Sodium += float64(e.Totals.Sodium)
+
+		if rank := slices.Index(constant.ScoreToLetter, e.Grade); rank >= 0 {
+			w := math.Max(e.Grams, 1)
+			gradeWeighted += float64(rank) * w
+			grams += w
+		}
+	}
+	if grams > 0 {
+		d.AverageGrade = constant.ScoreToLetter[int(math.Round(gradeWeighted/grams))]
+	}
+
+	d.EnergyMet = t.EnergyKcal <= 0 || math.Abs(d.EnergyKcal-t.EnergyKcal) <= t.EnergyKcal*EnergyTolerance
+	d.SodiumMet = d.Sodium <= t.SodiumMax
+	d.SugarsMet = d.Sugars <= t.SugarsMax
+	d.Met = d.Entries > 0 && d.EnergyMet && d.SodiumMet && d.SugarsMet
+
+	d.EnergyKcal = round(d.EnergyKcal)
+	d.Protein = round(d.Protein)
+	d.Fibre = round(d.Fibre)
+	d.Sugars = round(d.Sugars)
+	d.Sodium = round(d.Sodium)
+	return d
+}
+
+// Days summarises every date from first to last inclusive, in loc.
+func Days(ctx context.Context, s Store, userID string, first, last time.Time, loc *time.Location, t models.LogTargets) ([]models.DaySummary, error) {
+	first = startOfDay(first, loc)
+	last = startOfDay(last, loc)
+
+	entries, err := s.List(ctx, userID, first, last.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := map[string][]models.LogEntry{}
+	for _, e := range entries {
+		date := e.Time.In(loc).Format(DateLayout)
+		byDate[date] = append(byDate[date], e)
+	}
+
+	var out []models.DaySummary
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		date := d.Format(DateLayout)
+		out = append(out, Day(date, byDate[date], t))
+	}
+	return out, nil
+}
+
+// Week summarises the seven days ending on end and counts the streak of
+// target-meeting days up to end, looking further back than the week if needed.
+func Week(ctx context.Context, s Store, userID string, end time.Time, loc *time.Location, t models.LogTargets) (models.WeekSummary, error) {
+	end = startOfDay(end, loc)
+	start := end.AddDate(0, 0, -6)
+
+	days, err := Days(ctx, s, userID, start, end, loc, t)
+	if err != nil {
+		return models.WeekSummary{}, err
+	}
+
+	w := models.WeekSummary{From: start.Format(DateLayout), To: end.Format(DateLayout), Targets: t, Days: days}
+	var logged, gradeWeighted float64
+	for _, d := range days {
+		if d.Met {
+			w.DaysMet++
+		}
+		if d.Entries == 0 {
+			continue
+		}
+		logged++
+		w.EnergyKcal += d.EnergyKcal
+		w.Sugars += d.Sugars
+		w.Sodium += d.Sodium
+		if rank := slices.Index(constant.ScoreToLetter, d.AverageGrade); rank >= 0 {
+			gradeWeighted += float64(rank)
+		}
+	}
+	if logged > 0 {
+		w.EnergyKcal = round(w.EnergyKcal / logged)
+		w.Sugars = round(w.Sugars / logged)
+		w.Sodium = round(w.Sodium / logged)
+		w.AverageGrade = constant.ScoreToLetter[int(math.Round(gradeWeighted/logged))]
+	}
+
+	w.Streak, err = Streak(ctx, s, userID, end, loc, t)
+	return w, err
+}
+
+// Streak counts consecutive days meeting the targets, ending on end. A day
+// without entries breaks it, except today before the first meal is logged.
+func Streak(ctx context.Context, s Store, userID string, end time.Time, loc *time.Location, t models.LogTargets) (int, error) {
+	end = startOfDay(end, loc)
+	today := end.Equal(startOfDay(now(), loc))
+	streak := 0
+	// a month at a time so a long streak doesn't mean one huge read
+	for streak < MaxStreak {
+		first := end.AddDate(0, 0, -29)
+		days, err := Days(ctx, s, userID, first, end, loc, t)
+		if err != nil {
+			return 0, err
+		}
+		for i := len(days) - 1; i >= 0; i-- {
+			if today {
+				today = false
+				if days[i].Entries == 0 {
+					continue
+				}
+			}
+			if !days[i].Met {
+				return streak, nil
+			}
+			streak++
+		}
+		end = first.AddDate(0, 0, -1)
+	}
+	return min(streak, MaxStreak), nil
+}
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/estimate"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/rda"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type micronutrientRequest struct {
+	Diet     string `json:"diet"`
+	Age      int    `json:"age"`
+	Gender   string `json:"gender"`
+	Pregnant bool   `json:"pregnant"`
+	// "meal" or "day", defaults to meal
+	Scope string `json:"scope"`
+}
+
+func micronutrients(c *fiber.Ctx) error {
+	var req micronutrientRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid request data", nil, "")
+	}
+	if strings.TrimSpace(req.Diet) == "" {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "diet cannot be empty", nil, "")
+	}
+
+	profile := rda.Profile{Age: req.Age, Gender: req.Gender, Pregnant: req.Pregnant}
+	if err := profile.Validate(); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	scope := strings.ToLower(req.Scope)
+	switch scope {
+	case "":
+		scope = rda.ScopeMeal
+	case rda.ScopeMeal, rda.ScopeDay:
+	default:
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "scope must be meal or day", nil, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	items, err := estimate.Items(ctx, LLMProvider, FoodIndex, req.Diet)
+	if err != nil {
+		return util.ResponseAPI(c, llmErrorStatus(err), err.Error(), nil, "")
+	}
+
+	meal := cal.Combine(items)
+
+	var eaten models.Micronutrients
+	if meal.Totals.Micronutrients != nil {
+		eaten = *meal.Totals.Micronutrients
+	}
+	coverage := 0.0
+	if meal.Grams > 0 {
+		coverage = meal.MicronutrientGrams / meal.Grams
+	}
+
+	analysis := rda.Analyse(eaten, coverage, profile, scope)
+	return util.ResponseAPI(c, fiber.StatusOK, "micronutrients analysed successfully", analysis, "")
+}
@@ -0,0 +1,21 @@
+package models
+
+// BatchRow is one product in a batch upload. Line is the CSV line or the
+// 1-based array position, so errors can be traced back to the source.
+type BatchRow struct {
+	Line int
+	Name string
+	NutritionalData
+	ScoreType string
+	Version   string
+}
+
+type BatchResult struct {
+	Line      int               `json:"line"`
+	Name      string            `json:"name"`
+	ScoreType string            `json:"scoreType,omitempty"`
+	Version   Version           `json:"version,omitempty"`
+	Score     *NutritionalScore `json:"score,omitempty"`
+	Grade     string            `json:"grade,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PlanRequest is the user payload sent with SystemDietPlan.
+type PlanRequest struct {
+	Height     int            `json:"height"`
+	Weight     int            `json:"weight"`
+	BloodGroup string         `json:"bloodGroup"`
+	Gender     string         `json:"gender"`
+	Targets    *EnergyTargets `json:"targets,omitempty"`
+	// HealthIssue codes from the go-server survey and the limits they imply
+	Conditions  []string `json:"conditions,omitempty"`
+	Constraints []string `json:"constraints,omitempty"`
+	// dietary patterns such as vegan or jain, and allergens to leave out
+	Patterns  []string `json:"patterns,omitempty"`
+	Allergens []string `json:"allergens,omitempty"`
+}
+
+type Macros struct {
+	EnergyKcal   float64 `json:"energyKcal"`
+	Protein      float64 `json:"protein"`
+	Carbohydrate float64 `json:"carbohydrate"`
+	Fat          float64 `json:"fat"`
+	Fibre        float64 `json:"fibre"`
+}
+
+type PlanItem struct {
+	Food    string  `json:"food"`
+	Portion string  `json:"portion"`
+	Grams   float64 `json:"grams"`
+}
+
+type PlanMeal struct {
+	Name   string     `json:"name"`
+	Time   string     `json:"time"`
+	Items  []PlanItem `json:"items"`
+	Macros Macros     `json:"macros"`
+}
+
+type DietPlan struct {
+	Meals []PlanMeal `json:"meals"`
+	Notes []string   `json:"notes"`
+}
+
+// Text renders the plan as the plain-text timetable older clients display.
+func (p DietPlan) Text() string {
+	var b strings.Builder
+	for _, m := range p.Meals {
+		fmt.Fprintf(&b, "**%s (%s)**\n", m.Name, m.Time)
+		for _, it := range m.Items {
+			fmt.Fprintf(&b, "- %s: %s (%.0f g)\n", it.Food, it.Portion, it.Grams)
+		}
+		fmt.Fprintf(&b, "Approx. %.0f kcal, %.0f g protein, %.0f g carbs, %.0f g fat, %.0f g fibre\n\n",
+			m.Macros.EnergyKcal, m.Macros.Protein, m.Macros.Carbohydrate, m.Macros.Fat, m.Macros.Fibre)
+	}
+	for _, n := range p.Notes {
+		fmt.Fprintf(&b, "- %s\n", n)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// PlanViolation is a plan item that breaks the user's dietary patterns or
+// allergens, with a reason such as "vegan excludes dairy" for each.
+type PlanViolation struct {
+	Meal    string   `json:"meal"`
+	Food    string   `json:"food"`
+	Reasons []string `json:"reasons"`
+}
+
+func (v PlanViolation) String() string {
+	return fmt.Sprintf("%s in %s (%s)", v.Food, v.Meal, strings.Join(v.Reasons, ", "))
+}
+
+// PlanCheck compares one daily total of a generated plan with its target.
+// Kind is "min", "max" or "approx" (within 10%).
+type PlanCheck struct {
+	Nutrient string  `json:"nutrient"`
+	Unit     string  `json:"unit"`
+	Kind     string  `json:"kind"`
+	Target   float64 `json:"target"`
+	Actual   float64 `json:"actual"`
+	Met      bool    `json:"met"`
+}
+
+// SolvedPlan is a plan built from the local food table, with how close it
+// came to each target and the Nutri-Score grade of every food used.
+type SolvedPlan struct {
+	Plan   DietPlan          `json:"plan"`
+	Checks []PlanCheck       `json:"checks"`
+	Grades map[string]string `json:"grades"`
+	Sodium float64           `json:"sodium"`
+}
@@ -0,0 +1,22 @@
+package models
+
+type MacroTargets struct {
+	EnergyKcal   float64 `json:"energyKcal"`
+	Protein      float64 `json:"protein"`
+	Carbohydrate float64 `json:"carbohydrate"`
+	Fat          float64 `json:"fat"`
+	Fibre        float64 `json:"fibre"`
+}
+
+type EnergyTargets struct {
+	BMI               float64      `json:"bmi"`
+	BMICategory       string       `json:"bmiCategory"`
+	BMRMifflinStJeor  float64      `json:"bmrMifflinStJeor"`
+	BMRHarrisBenedict float64      `json:"bmrHarrisBenedict"`
+	ActivityLevel     string       `json:"activityLevel"`
+	ActivityFactor    float64      `json:"activityFactor"`
+	TDEE              float64      `json:"tdee"`
+	Goal              string       `json:"goal"`
+	Macros            MacroTargets `json:"macros"`
+	AgeAssumed        bool         `json:"ageAssumed"`
+}
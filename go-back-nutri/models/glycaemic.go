@@ -0,0 +1,21 @@
+package models
+
+type ItemGlycaemicLoad struct {
+	Name         string   `json:"name"`
+	Grams        float64  `json:"grams"`
+	Carbohydrate float64  `json:"carbohydrate"`
+	GI           *float64 `json:"gi"`
+	// nil when the item's GI is unknown
+	GL *float64 `json:"gl"`
+}
+
+type GlycaemicLoad struct {
+	GL           float64 `json:"gl"`
+	Class        string  `json:"class"`
+	Carbohydrate float64 `json:"carbohydrate"`
+	// carbohydrate-weighted over the items with a known GI
+	GI *float64 `json:"gi"`
+	// share of the carbohydrate that had a GI, 0 to 1
+	Coverage float64             `json:"coverage"`
+	Items    []ItemGlycaemicLoad `json:"items"`
+}
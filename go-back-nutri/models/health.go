@@ -0,0 +1,12 @@
+package models
+
+// HealthWarning flags a nutrient in a meal that goes over the limit set for
+// one of the user's health conditions.
+type HealthWarning struct {
+	Condition string  `json:"condition"`
+	Nutrient  string  `json:"nutrient"`
+	Unit      string  `json:"unit"`
+	Amount    float64 `json:"amount"`
+	Limit     float64 `json:"limit"`
+	Message   string  `json:"message"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// LogEntry is one scored meal in a user's log.
+type LogEntry struct {
+	ID      string          `json:"id"`
+	UserID  string          `json:"userId"`
+	Time    time.Time       `json:"time"`
+	Diet    string          `json:"diet"`
+	Version Version         `json:"version"`
+	Items   []FoodItem      `json:"items"`
+	Grams   float64         `json:"grams"`
+	Totals  NutritionalData `json:"totals"`
+	Score   int             `json:"score"`
+	Grade   string          `json:"grade"`
+}
+
+// LogTargets are the daily limits a day in the log is judged against. A zero
+// EnergyKcal skips the energy check.
+type LogTargets struct {
+	EnergyKcal float64 `json:"energyKcal"`
+	SodiumMax  float64 `json:"sodiumMax"`
+	SugarsMax  float64 `json:"sugarsMax"`
+}
+
+type DaySummary struct {
+	Date    string `json:"date"`
+	Entries int    `json:"entries"`
+	// grams-weighted over the day's meals; empty without entries
+	AverageGrade string  `json:"averageGrade"`
+	EnergyKcal   float64 `json:"energyKcal"`
+	Protein      float64 `json:"protein"`
+	Fibre        float64 `json:"fibre"`
+	Sugars       float64 `json:"sugars"`
+	Sodium       float64 `json:"sodium"`
+	EnergyMet    bool    `json:"energyMet"`
+	SodiumMet    bool    `json:"sodiumMet"`
+	SugarsMet    bool    `json:"sugarsMet"`
+	// every check passed on a day with at least one entry
+	Met bool `json:"met"`
+}
+
+type WeekSummary struct {
+	From         string       `json:"from"`
+	To           string       `json:"to"`
+	Targets      LogTargets   `json:"targets"`
+	Days         []DaySummary `json:"days"`
+	AverageGrade string       `json:"averageGrade"`
+	// daily averages over the days that have entries
+	EnergyKcal float64 `json:"energyKcal"`
+	Sugars     float64 `json:"sugars"`
+	Sodium     float64 `json:"sodium"`
+	DaysMet    int     `json:"daysMet"`
+	// consecutive days meeting the targets, ending on To
+	Streak int `json:"streak"`
+}
@@ -0,0 +1,64 @@
+package models
+
+// Micronutrients are per 100 g like the rest of NutritionalData. Units are mg
+// for iron, calcium, vitamin C, potassium and zinc; µg for vitamin A (RAE),
+// vitamin D, vitamin B12 and folate (DFE).
+type Micronutrients struct {
+	Iron       float64 `json:"iron"`
+	Calcium    float64 `json:"calcium"`
+	VitaminA   float64 `json:"vitaminA"`
+	VitaminC   float64 `json:"vitaminC"`
+	VitaminD   float64 `json:"vitaminD"`
+	VitaminB12 float64 `json:"vitaminB12"`
+	Folate     float64 `json:"folate"`
+	Potassium  float64 `json:"potassium"`
+	Zinc       float64 `json:"zinc"`
+}
+
+func (m Micronutrients) Scale(f float64) Micronutrients {
+	return Micronutrients{
+		Iron:       m.Iron * f,
+		Calcium:    m.Calcium * f,
+		VitaminA:   m.VitaminA * f,
+		VitaminC:   m.VitaminC * f,
+		VitaminD:   m.VitaminD * f,
+		VitaminB12: m.VitaminB12 * f,
+		Folate:     m.Folate * f,
+		Potassium:  m.Potassium * f,
+		Zinc:       m.Zinc * f,
+	}
+}
+
+func (m Micronutrients) Add(o Micronutrients) Micronutrients {
+	return Micronutrients{
+		Iron:       m.Iron + o.Iron,
+		Calcium:    m.Calcium + o.Calcium,
+		VitaminA:   m.VitaminA + o.VitaminA,
+		VitaminC:   m.VitaminC + o.VitaminC,
+		VitaminD:   m.VitaminD + o.VitaminD,
+		VitaminB12: m.VitaminB12 + o.VitaminB12,
+		Folate:     m.Folate + o.Folate,
+		Potassium:  m.Potassium + o.Potassium,
+		Zinc:       m.Zinc + o.Zinc,
+	}
+}
+
+type MicronutrientStatus struct {
+	Nutrient   string   `json:"nutrient"`
+	Unit       string   `json:"unit"`
+	Amount     float64  `json:"amount"`
+	RDA        float64  `json:"rda"`
+	Target     float64  `json:"target"`
+	PercentRDA float64  `json:"percentRda"`
+	UpperLimit *float64 `json:"upperLimit"`
+	Status     string   `json:"status"`
+}
+
+type MicronutrientAnalysis struct {
+	Scope     string                `json:"scope"`
+	Group     string                `json:"group"`
+	Coverage  float64               `json:"coverage"`
+	Nutrients []MicronutrientStatus `json:"nutrients"`
+	Deficits  []string              `json:"deficits"`
+	Excesses  []string              `json:"excesses"`
+}
@@ -0,0 +1,39 @@
+package models
+
+// Rating is the result of one front-of-pack scheme. Label is the headline a
+// pack would show; Detail holds the scheme's own breakdown.
+type Rating struct {
+	Scheme string `json:"scheme"`
+	Label  string `json:"label"`
+	Detail any    `json:"detail"`
+}
+
+type NutriScoreRating struct {
+	Grade string           `json:"grade"`
+	Score NutritionalScore `json:"score"`
+}
+
+type TrafficLight struct {
+	Nutrient string  `json:"nutrient"`
+	Colour   string  `json:"colour"`
+	Per100g  float64 `json:"per100g"`
+	// only set when a portion size was given
+	PerPortion *float64 `json:"perPortion,omitempty"`
+	PercentRI  *float64 `json:"percentRi,omitempty"`
+}
+
+type TrafficLights struct {
+	Basis        string         `json:"basis"`
+	PortionGrams float64        `json:"portionGrams,omitempty"`
+	Lights       []TrafficLight `json:"lights"`
+}
+
+type HealthStarRating struct {
+	Stars          float64 `json:"stars"`
+	Score          int     `json:"score"`
+	Baseline       int     `json:"baseline"`
+	FruitsPoints   int     `json:"fruitsPoints"`
+	ProteinPoints  int     `json:"proteinPoints"`
+	FibrePoints    int     `json:"fibrePoints"`
+	ProteinCounted bool    `json:"proteinCounted"`
+}
@@ -6,18 +6,35 @@ var SystemGrade = `
 	Input: A comma-separated paragraph of food names, e.g. "orange, 2 milk, lemons"
 
 	Your task:
-	- Extract each food item (ignore quantities like "2")
+	- Extract each food item
+	- Estimate the total grams eaten for each item from its quantity (e.g. "2 milk" is two glasses); use one typical serving when no quantity is given
 	- For each item, return a **separate JSON object**, exactly in this format:
 
 	{
+		"Name": "milk",
+		"Grams": 500,
 		"IsWater": false,
+		"IsBeverage": false,
+		"IsCheese": false,
 		"Energy": 2000,
 		"Sugars": 15,
 		"Fibre": 2,
 		"Protein": 3,
 		"Fruits": 45,
 		"Sodium": 500,
-		"SaturatedFattyAcids": 4
+		"SaturatedFattyAcids": 4,
+		"Fat": 7,
+		"Carbohydrate": 12,
+		"GlycaemicIndex": 40,
+		"Iron": 0.5,
+		"Calcium": 120,
+		"VitaminA": 50,
+		"VitaminC": 2,
+		"VitaminD": 0.1,
+		"VitaminB12": 0.4,
+		"Folate": 10,
+		"Potassium": 150,
+		"Zinc": 0.4
 	}
 
 	Strict Output Rules:
@@ -27,7 +44,10 @@ var SystemGrade = `
 	- Do not hallucinate or invent food items.
 	- If an item is unrecognized, skip it silently.
 	- All keys, spelling, and ordering must be exact.
-	- All values must be approximate realistic estimates per food item.
+	- IsWater is true only for plain water, IsBeverage for any other drink, IsCheese for cheese.
+	- Carbohydrate is available carbohydrate in grams, excluding fibre. GlycaemicIndex uses glucose = 100; omit it when the item has almost no carbohydrate.
+	- Micronutrient units: Iron, Calcium, VitaminC, Potassium and Zinc in mg; VitaminA (RAE), VitaminD, VitaminB12 and Folate (DFE) in µg.
+	- Nutrient values must be approximate realistic estimates per 100 g of the food item, not per serving.
 
 	Any deviation from format, content, or structure is unacceptable.
 `
@@ -36,4 +56,68 @@ var SystemDietPlan = `
 	You will be given a list of food items from my current meal, along with my gender, blood group, height, and weight.
 	Your task is to analyze the nutritional composition of the meal and suggest a revised diet plan for my next meal. This plan must be balanced and include optimal amounts of proteins, essential vitamins, minerals, and other key nutrients.
 	Use the input data to detect any deficiencies, excesses, or imbalances, and recommend precise adjustments to improve overall health and performance.
+	When the input includes "targets", the meals together must add up to targets.macros (daily energy in kcal, protein, carbohydrate, fat and fibre in grams) within 10%.
+	When the input includes "conditions", every meal must follow each sentence in "constraints"; choose foods that suit those conditions and mention the relevant advice in "notes".
+	When the input includes "patterns", no item may break any of them: vegetarian (also called lacto-vegetarian) allows no meat, fish or eggs, eggetarian no meat or fish but eggs are fine, vegan no animal products or honey, pescatarian no meat, jain no meat, fish, eggs, honey or root vegetables such as potato, onion, garlic, ginger and carrot, halal no pork, lard, gelatin or alcohol, gluten-free no wheat, barley, rye or oats, lactose-free no dairy.
+	When the input includes "allergens", no item may contain any of them, even as an ingredient. Name every item by its main ingredients (e.g. "tofu curry", not "protein curry") so it can be checked.
+
+	Return only one JSON object matching this schema, with no markdown or extra text:
+
+	{
+		"type": "object",
+		"required": ["meals", "notes"],
+		"additionalProperties": false,
+		"properties": {
+			"meals": {
+				"type": "array",
+				"minItems": 1,
+				"items": {
+					"type": "object",
+					"required": ["name", "time", "items", "macros"],
+					"additionalProperties": false,
+					"properties": {
+						"name": {"type": "string", "description": "e.g. Breakfast"},
+						"time": {"type": "string", "pattern": "^([01][0-9]|2[0-3]):[0-5][0-9]$"},
+						"items": {
+							"type": "array",
+							"minItems": 1,
+							"items": {
+								"type": "object",
+								"required": ["food", "portion", "grams"],
+								"additionalProperties": false,
+								"properties": {
+									"food": {"type": "string"},
+									"portion": {"type": "string", "description": "e.g. 1 katori"},
+									"grams": {"type": "number", "exclusiveMinimum": 0}
+								}
+							}
+						},
+						"macros": {
+							"type": "object",
+							"required": ["energyKcal", "protein", "carbohydrate", "fat", "fibre"],
+							"additionalProperties": false,
+							"properties": {
+								"energyKcal": {"type": "number", "minimum": 0},
+								"protein": {"type": "number", "minimum": 0},
+								"carbohydrate": {"type": "number", "minimum": 0},
+								"fat": {"type": "number", "minimum": 0},
+								"fibre": {"type": "number", "minimum": 0}
+							}
+						}
+					}
+				}
+			},
+			"notes": {"type": "array", "items": {"type": "string"}}
+		}
+	}
+`
+
+var SystemRephrasePlan = `
+	You will be given a day's diet plan as a JSON object that was computed to meet calorie and nutrient targets.
+	Rewrite it for the person who will follow it:
+	- Replace each "portion" with a familiar household measure (e.g. "1 katori", "2 medium rotis", "1 cup").
+	- Rewrite "notes" as two to four short, friendly tips about the plan.
+
+	Do not add, remove, rename or reorder meals or items, and do not change any "name", "time", "food", "grams" or "macros" value.
+	Return only the JSON object in the same schema, with no markdown or extra text.
 `
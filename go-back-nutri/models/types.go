@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 type ScoreType int
 
 const (
@@ -9,11 +11,92 @@ const (
 	Beverage
 )
 
+// Auto leaves the type to be inferred from the product flags. It sits outside
+// the iota block so the numbers scores already carry don't move.
+const Auto ScoreType = -1
+
+var scoreTypeNames = map[ScoreType]string{
+	Food:     "food",
+	Water:    "water",
+	Cheese:   "cheese",
+	Beverage: "beverage",
+	Auto:     "auto",
+}
+
+func (st ScoreType) String() string {
+	if name, ok := scoreTypeNames[st]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func ParseScoreType(name string) (ScoreType, bool) {
+	for st, n := range scoreTypeNames {
+		if strings.EqualFold(n, strings.TrimSpace(name)) {
+			return st, true
+		}
+	}
+	return Food, false
+}
+
+type Version string
+
+const (
+	V2017 Version = "2017"
+	V2023 Version = "2023"
+)
+
+var Versions = []Version{V2017, V2023}
+
+// ParseVersion defaults to 2017 so existing callers keep their grades.
+func ParseVersion(name string) (Version, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return V2017, true
+	}
+	for _, v := range Versions {
+		if string(v) == name {
+			return v, true
+		}
+	}
+	return V2017, false
+}
+
+// ScoreRule records which branch of the protein cut-off was applied.
+type ScoreRule string
+
+const (
+	RuleNegativeBelowCutOff ScoreRule = "negative_below_cut_off"
+	RuleFruitsAtMaximum     ScoreRule = "fruits_at_maximum"
+	RuleCheese              ScoreRule = "cheese"
+	RuleProteinExcluded     ScoreRule = "protein_excluded"
+)
+
 type NutritionalScore struct {
-	Value     int
-	Positive  int
-	Negative  int
-	ScoreType ScoreType
+	Value          int
+	Positive       int
+	Negative       int
+	ProteinCounted bool
+	Rule           ScoreRule
+	ScoreType      ScoreType
+	Version        Version
+}
+
+type Component struct {
+	Nutrient  string   `json:"nutrient"`
+	Negative  bool     `json:"negative"`
+	Value     float64  `json:"value"`
+	Lower     *float64 `json:"lower"`
+	Upper     *float64 `json:"upper"`
+	Points    int      `json:"points"`
+	MaxPoints int      `json:"maxPoints"`
+	Counted   bool     `json:"counted"`
+}
+
+type ScoreExplanation struct {
+	Components []Component      `json:"components"`
+	Score      NutritionalScore `json:"score"`
+	Grade      string           `json:"grade"`
 }
 
 type EnergyKJ float64
@@ -23,9 +106,13 @@ type ProteinGram float64
 type FruitsPercent float64
 type SodiumMilligram float64
 type SaturatedFattyAcidsGram float64
+type FatGram float64
+type CarbohydrateGram float64
 
 type NutritionalData struct {
 	IsWater             bool
+	IsBeverage          bool
+	IsCheese            bool
 	Energy              EnergyKJ
 	Sugars              SugarGram
 	Fibre               FibreGram
@@ -33,6 +120,75 @@ type NutritionalData struct {
 	Fruits              FruitsPercent
 	Sodium              SodiumMilligram
 	SaturatedFattyAcids SaturatedFattyAcidsGram
+	// total fat; not part of Nutri-Score, used by the traffic-light scheme
+	Fat FatGram
+	// available carbohydrate, fibre excluded
+	Carbohydrate CarbohydrateGram
+	// nil when unknown; for a meal it is weighted by each item's carbohydrate
+	GlycaemicIndex *float64
+	// nil when the source had no micronutrient data
+	Micronutrients *Micronutrients
+}
+
+const (
+	SourceDatabase = "database"
+	SourceLLM      = "llm"
+	SourceRequest  = "request"
+)
+
+type FoodItem struct {
+	Name   string
+	Grams  float64
+	Data   NutritionalData
+	Source string
+}
+
+// Meal holds every parsed item, the quantity-weighted per-100g profile that
+// gets scored, and the absolute amounts eaten. Totals.Fruits stays a percentage.
+type Meal struct {
+	Items   []FoodItem
+	Grams   float64
+	Per100g NutritionalData
+	Totals  NutritionalData
+	// grams of the items that carried micronutrient data
+	MicronutrientGrams float64
+}
+
+type ItemScore struct {
+	Name   string           `json:"name"`
+	Grams  float64          `json:"grams"`
+	Source string           `json:"source"`
+	Data   NutritionalData  `json:"data"`
+	Score  NutritionalScore `json:"score"`
+	Grade  string           `json:"grade"`
+}
+
+type MealScore struct {
+	Grade     string           `json:"grade"`
+	Score     NutritionalScore `json:"score"`
+	Grams     float64          `json:"grams"`
+	Per100g   NutritionalData  `json:"meal"`
+	Totals    NutritionalData  `json:"totals"`
+	Items     []ItemScore      `json:"items"`
+	Glycaemic GlycaemicLoad    `json:"glycaemic"`
+}
+
+type RecipeIngredient struct {
+	Name     string          `json:"name"`
+	Grams    float64         `json:"grams"`
+	Category string          `json:"category"`
+	Source   string          `json:"source"`
+	Data     NutritionalData `json:"data"`
+}
+
+type RecipeScore struct {
+	Name        string             `json:"name"`
+	RawGrams    float64            `json:"rawGrams"`
+	CookedGrams float64            `json:"cookedGrams"`
+	Per100g     NutritionalData    `json:"per100g"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+	Score       NutritionalScore   `json:"score"`
+	Grade       string             `json:"grade"`
 }
 
 type Diet string
@@ -46,3 +202,21 @@ type Choice struct {
 type LLMResponse struct {
 	Choices []Choice `json:"choices"`
 }
+
+// ProductScore compares our grade for a packaged product with the one Open
+// Food Facts declares.
+type ProductScore struct {
+	Barcode       string           `json:"barcode"`
+	Name          string           `json:"name"`
+	Brand         string           `json:"brand"`
+	Source        string           `json:"source"`
+	Data          NutritionalData  `json:"data"`
+	Score         NutritionalScore `json:"score"`
+	Grade         string           `json:"grade"`
+	DeclaredGrade string           `json:"declaredGrade"`
+	// the revision OFF grades with, empty when no grade is declared
+	DeclaredVersion Version `json:"declaredVersion,omitempty"`
+	// nil when the product declares no grade or it was graded with another
+	// version than ours
+	Matches *bool `json:"matches"`
+}
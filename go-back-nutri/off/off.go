@@ -0,0 +1,155 @@
+package off
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+var ErrNotFound = errors.New("product not found")
+
+const DefaultTimeout = 10 * time.Second
+
+const (
+	SourceDump   = "dump"
+	SourceMirror = "mirror"
+)
+
+// EAN-8, UPC-A, EAN-13 and GTIN-14
+var barcodeRe = regexp.MustCompile(`^[0-9]{8,14}$`)
+
+func ValidBarcode(code string) bool {
+	return barcodeRe.MatchString(code)
+}
+
+// Index holds products from a local JSONL dump, keyed by barcode. Only records
+// with a usable nutrition panel are kept.
+type Index struct {
+	products map[string]Product
+}
+
+// LoadJSONL reads one product per line, as in the official OFF JSONL export.
+// Lines that don't parse are skipped; the dump has plenty of them.
+func LoadJSONL(r io.Reader) (*Index, error) {
+	ix := &Index{products: map[string]Product{}}
+
+	sc := bufio.NewScanner(r)
+	// a single product line can run to a few hundred KB
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var p Product
+		if err := json.Unmarshal(sc.Bytes(), &p); err != nil {
+			continue
+		}
+		if p.Code == "" || !p.HasPanel() {
+			continue
+		}
+		ix.products[p.Code] = p
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading product dump: %w", err)
+	}
+	return ix, nil
+}
+
+func LoadFile(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadJSONL(f)
+}
+
+func (ix *Index) Len() int {
+	if ix == nil {
+		return 0
+	}
+	return len(ix.products)
+}
+
+func (ix *Index) Get(code string) (Product, bool) {
+	if ix == nil {
+		return Product{}, false
+	}
+	p, ok := ix.products[code]
+	return p, ok
+}
+
+// Mirror fetches single products from an OFF-compatible server, e.g.
+// https://world.openfoodfacts.org or a self-hosted copy.
+type Mirror struct {
+	baseURL string
+	client  *resty.Client
+}
+
+func NewMirror(baseURL string, timeout time.Duration) *Mirror {
+	return &Mirror{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  resty.New().SetTimeout(timeout).SetHeader("User-Agent", "go-back-nutri/1.0"),
+	}
+}
+
+func (m *Mirror) Get(ctx context.Context, code string) (Product, error) {
+	res, err := m.client.R().
+		SetContext(ctx).
+		Get(m.baseURL + "/api/v2/product/" + code + ".json")
+	if err != nil {
+		return Product{}, fmt.Errorf("request error: %w", err)
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return Product{}, ErrNotFound
+	}
+	if res.IsError() {
+		return Product{}, fmt.Errorf("error response from product mirror: %s", res.Status())
+	}
+
+	var parsed struct {
+		Status  int     `json:"status"`
+		Product Product `json:"product"`
+	}
+	if err := json.Unmarshal(res.Body(), &parsed); err != nil {
+		return Product{}, fmt.Errorf("unmarshal response: %w", err)
+	}
+	if parsed.Status != 1 {
+		return Product{}, ErrNotFound
+	}
+	if parsed.Product.Code == "" {
+		parsed.Product.Code = code
+	}
+	return parsed.Product, nil
+}
+
+// Source looks a barcode up in the local dump first and falls back to the
+// mirror. Either may be nil.
+type Source struct {
+	Index  *Index
+	Mirror *Mirror
+}
+
+func (s Source) Lookup(ctx context.Context, code string) (Product, string, error) {
+	if p, ok := s.Index.Get(code); ok {
+		return p, SourceDump, nil
+	}
+	if s.Mirror == nil {
+		return Product{}, "", ErrNotFound
+	}
+	p, err := s.Mirror.Get(ctx, code)
+	if err != nil {
+		return Product{}, "", err
+	}
+	if !p.HasPanel() {
+		return Product{}, "", fmt.Errorf("%w: %s has no nutrition panel", ErrNotFound, code)
+	}
+	return p, SourceMirror, nil
+}
@@ -0,0 +1,78 @@
+package off
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const dump = `{"code":"3017620422003","product_name":"Nutella","brands":"Ferrero","nutriscore_grade":"e","categories_tags":["en:spreads"],"nutriments":{"energy-kj_100g":2252,"sugars_100g":56.3,"fiber_100g":0,"proteins_100g":6.3,"saturated-fat_100g":10.6,"fat_100g":30.9,"salt_100g":0.107}}
+{"code":"5449000000996","product_name":"Coca-Cola","categories_tags":["en:beverages","en:sodas"],"nutriments":{"energy-kcal_100g":"42","sugars_100g":"10.6","saturated-fat_100g":0,"sodium_100g":0}}
+{"code":"0000000000001","product_name":"no panel","nutriments":{}}
+not json
+`
+
+func TestLoadJSONL(t *testing.T) {
+	ix, err := LoadJSONL(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ix.Len() != 2 {
+		t.Fatalf("Expected 2 products with a panel, got %d", ix.Len())
+	}
+
+	nutella, _ := ix.Get("3017620422003")
+	data := nutella.Data()
+	// 0.107 g salt is 42.8 mg sodium
+	if data.Energy != 2252 || data.Sodium < 42.7 || data.Sodium > 42.9 || data.Fat != 30.9 {
+		t.Errorf("Expected nutriments mapped to our units, got %+v", data)
+	}
+	if nutella.DeclaredGrade() != "E" {
+		t.Errorf("Expected declared grade E, got %q", nutella.DeclaredGrade())
+	}
+
+	cola, _ := ix.Get("5449000000996")
+	data = cola.Data()
+	if !data.IsBeverage || data.Sugars != 10.6 || data.Energy < 175 || data.Energy > 176 {
+		t.Errorf("Expected a beverage with string values parsed and kcal converted, got %+v", data)
+	}
+}
+
+func TestSourceFallsBackToMirror(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/product/4006381333931.json" {
+			w.Write([]byte(`{"status":0}`))
+			return
+		}
+		w.Write([]byte(`{"status":1,"product":{"product_name":"Apple juice","categories_tags":["en:beverages"],"nutriments":{"energy_100g":190,"sugars_100g":10,"saturated-fat_100g":0,"salt_100g":0,"fruits-vegetables-nuts_100g":100}}}`))
+	}))
+	defer srv.Close()
+
+	ix, _ := LoadJSONL(strings.NewReader(dump))
+	src := Source{Index: ix, Mirror: NewMirror(srv.URL+"/", time.Second)}
+
+	if _, from, err := src.Lookup(context.Background(), "3017620422003"); err != nil || from != SourceDump {
+		t.Errorf("Expected the dump to answer first, got %s %v", from, err)
+	}
+
+	p, from, err := src.Lookup(context.Background(), "4006381333931")
+	if err != nil || from != SourceMirror || p.Code != "4006381333931" || p.Data().Fruits != 100 {
+		t.Errorf("Expected the mirror product, got %+v %s %v", p, from, err)
+	}
+
+	if _, _, err := src.Lookup(context.Background(), "12345678"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestValidBarcode(t *testing.T) {
+	for code, want := range map[string]bool{"12345678": true, "3017620422003": true, "1234567": false, "30176204220a3": false} {
+		if ValidBarcode(code) != want {
+			t.Errorf("Expected %v for %s", want, code)
+		}
+	}
+}
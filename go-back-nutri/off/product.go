@@ -0,0 +1,108 @@
+package off
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/util"
+)
+
+// Product is the part of an Open Food Facts record we use. Nutriments values
+// arrive as numbers or strings depending on the dump, so they are kept raw.
+type Product struct {
+	Code            string         `json:"code"`
+	ProductName     string         `json:"product_name"`
+	Brands          string         `json:"brands"`
+	CategoriesTags  []string       `json:"categories_tags"`
+	NutriscoreGrade string         `json:"nutriscore_grade"`
+	Nutriments      map[string]any `json:"nutriments"`
+}
+
+// Nutriment returns the first of keys that holds a number.
+func (p Product) Nutriment(keys ...string) (float64, bool) {
+	for _, k := range keys {
+		switch v := p.Nutriments[k].(type) {
+		case float64:
+			return v, true
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// HasPanel reports whether the record carries enough of a nutrition panel to
+// score; energy, sugars, saturated fat and salt or sodium must be present.
+func (p Product) HasPanel() bool {
+	_, energy := p.Nutriment("energy-kj_100g", "energy_100g", "energy-kcal_100g")
+	_, sugars := p.Nutriment("sugars_100g")
+	_, sfa := p.Nutriment("saturated-fat_100g")
+	_, sodium := p.Nutriment("sodium_100g", "salt_100g")
+	return energy && sugars && sfa && sodium
+}
+
+func (p Product) hasCategory(tags ...string) bool {
+	for _, t := range tags {
+		if slices.Contains(p.CategoriesTags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Data maps the per-100g nutriments onto our units: sodium and salt are
+// reported in grams and converted to mg, and energy falls back to kcal.
+func (p Product) Data() models.NutritionalData {
+	data := models.NutritionalData{
+		IsWater:    p.hasCategory("en:waters", "en:spring-waters", "en:mineral-waters"),
+		IsBeverage: p.hasCategory("en:beverages"),
+		IsCheese:   p.hasCategory("en:cheeses"),
+	}
+	if data.IsWater {
+		data.IsBeverage = false
+	}
+
+	if kj, ok := p.Nutriment("energy-kj_100g", "energy_100g"); ok {
+		data.Energy = models.EnergyKJ(kj)
+	} else if kcal, ok := p.Nutriment("energy-kcal_100g"); ok {
+		data.Energy = util.EnergyFromKcal(kcal)
+	}
+
+	sugars, _ := p.Nutriment("sugars_100g")
+	fibre, _ := p.Nutriment("fiber_100g")
+	protein, _ := p.Nutriment("proteins_100g")
+	fruits, _ := p.Nutriment("fruits-vegetables-nuts_100g", "fruits-vegetables-legumes-estimate-from-ingredients_100g", "fruits-vegetables-nuts-estimate-from-ingredients_100g")
+	sfa, _ := p.Nutriment("saturated-fat_100g")
+	fat, _ := p.Nutriment("fat_100g")
+	carbs, _ := p.Nutriment("carbohydrates_100g")
+
+	data.Sugars = models.SugarGram(sugars)
+	data.Fibre = models.FibreGram(fibre)
+	data.Protein = models.ProteinGram(protein)
+	data.Fruits = models.FruitsPercent(fruits)
+	data.SaturatedFattyAcids = models.SaturatedFattyAcidsGram(sfa)
+	data.Fat = models.FatGram(fat)
+	data.Carbohydrate = models.CarbohydrateGram(carbs)
+
+	if sodium, ok := p.Nutriment("sodium_100g"); ok {
+		data.Sodium = models.SodiumMilligram(sodium * 1000)
+	} else if salt, ok := p.Nutriment("salt_100g"); ok {
+		data.Sodium = util.SodiumFromSalt(salt * 1000)
+	}
+
+	return data
+}
+
+// DeclaredGrade is the grade OFF computed, upper-cased, or "" when it has none.
+func (p Product) DeclaredGrade() string {
+	switch g := strings.ToUpper(p.NutriscoreGrade); g {
+	case "A", "B", "C", "D", "E":
+		return g
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+
+	"github.com/MishraShardendu22/energy"
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/health"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/planner"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type planRequest struct {
+	Height    float64  `json:"height"`
+	Weight    float64  `json:"weight"`
+	Age       int      `json:"age"`
+	Gender    string   `json:"gender"`
+	Activity  string   `json:"activity"`
+	Goal      string   `json:"goal"`
+	Patterns  []string `json:"patterns"`
+	Allergens []string `json:"allergens"`
+	// single-pattern form kept for older clients; merged into Patterns
+	Pattern    string   `json:"pattern"`
+	Conditions []string `json:"conditions"`
+	// mg a day; defaults to the WHO limit or the strictest condition limit
+	SodiumMax float64 `json:"sodiumMax"`
+	Version   string  `json:"version"`
+	// ask the LLM to reword portions and notes; the solved plan is kept if
+	// that fails
+	Rephrase bool `json:"rephrase"`
+}
+
+type planResponse struct {
+	models.SolvedPlan
+	Targets   models.EnergyTargets `json:"targets"`
+	Rephrased bool                 `json:"rephrased"`
+	Timetable string               `json:"timetable"`
+}
+
+// dayPlan builds a reproducible day plan from the local food table, with no
+// LLM needed unless rephrasing is asked for.
+func dayPlan(c *fiber.Ctx) error {
+	var req planRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid request data", nil, "")
+	}
+
+	in := energy.Input{
+		HeightCm: req.Height,
+		WeightKg: req.Weight,
+		Age:      req.Age,
+		Gender:   req.Gender,
+		Activity: req.Activity,
+		Goal:     req.Goal,
+	}
+	if err := in.Validate(); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	restrictions, err := foods.ParseRestrictions(append(req.Patterns, req.Pattern), req.Allergens)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+	conditions, err := health.Parse(req.Conditions)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+	version, ok := models.ParseVersion(req.Version)
+	if !ok {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid algorithm version", nil, "")
+	}
+	if req.SodiumMax < 0 {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "sodium limit cannot be negative", nil, "")
+	}
+
+	targets := health.AdjustTargets(energy.Calculate(in), conditions, req.Weight)
+	sodiumMax := req.SodiumMax
+	if limit, ok := health.DailyLimit(conditions, "sodium", req.Weight); ok && (sodiumMax == 0 || limit < sodiumMax) {
+		sodiumMax = limit
+	}
+
+	// the other condition limits are caps the solver keeps portions under
+	maximum := func(nutrient string) float64 {
+		limit, _ := health.DailyLimit(conditions, nutrient, req.Weight)
+		return limit
+	}
+
+	solved, err := planner.Solve(FoodIndex, planner.Request{
+		Targets: planner.Targets{
+			EnergyKcal:      targets.Macros.EnergyKcal,
+			Protein:         targets.Macros.Protein,
+			Fibre:           targets.Macros.Fibre,
+			SodiumMax:       sodiumMax,
+			ProteinMax:      maximum("protein"),
+			PotassiumMax:    maximum("potassium"),
+			SugarsMax:       maximum("sugars"),
+			CarbohydrateMax: maximum("carbohydrate"),
+		},
+		Restrictions: restrictions,
+		Version:      version,
+	})
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusUnprocessableEntity, err.Error(), nil, "")
+	}
+
+	res := planResponse{SolvedPlan: solved, Targets: targets}
+	if req.Rephrase {
+		ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+		defer cancel()
+
+		if plan, err := util.RephrasePlan(ctx, LLMProvider, solved.Plan); err == nil {
+			res.Plan, res.Rephrased = plan, true
+		}
+	}
+	res.Timetable = res.Plan.Text()
+
+	return util.ResponseAPI(c, fiber.StatusOK, "diet plan generated successfully", res, "")
+}
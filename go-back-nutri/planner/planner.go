@@ -0,0 +1,530 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/score"
+)
+
+// Targets are daily. Protein and fibre are minimums, sodium a maximum, and
+// energy should land within EnergyTolerance. The other maximums come from
+// health conditions and are left out when zero.
+type Targets struct {
+	EnergyKcal      float64
+	Protein         float64
+	Fibre           float64
+	SodiumMax       float64
+	ProteinMax      float64
+	PotassiumMax    float64
+	SugarsMax       float64
+	CarbohydrateMax float64
+}
+
+// limit is one optional maximum portions may not grow past.
+type limit struct {
+	nutrient, unit string
+	max            float64
+	per100g        func(foods.Food) float64
+	total          func(totals) float64
+}
+
+// limits lists sodium and then whichever condition maximums are set.
+func (t Targets) limits() []limit {
+	sodium := limit{"sodium", "mg", t.SodiumMax, func(f foods.Food) float64 { return f.Sodium }, func(s totals) float64 { return s.sodium }}
+	return append([]limit{sodium}, t.caps()...)
+}
+
+// caps lists the condition maximums no portion may grow past. Sodium is left
+// to Solve's swaps, as it always has a limit and most foods carry some.
+func (t Targets) caps() []limit {
+	all := []limit{
+		{"protein", "g", t.ProteinMax, func(f foods.Food) float64 { return f.Protein }, func(s totals) float64 { return s.protein }},
+		{"potassium", "mg", t.PotassiumMax, potassium, func(s totals) float64 { return s.potassium }},
+		{"sugars", "g", t.SugarsMax, func(f foods.Food) float64 { return f.Sugars }, func(s totals) float64 { return s.sugars }},
+		{"carbohydrate", "g", t.CarbohydrateMax, func(f foods.Food) float64 { return f.Carbohydrate }, func(s totals) float64 { return s.carbohydrate }},
+	}
+	var out []limit
+	for _, l := range all {
+		if l.max > 0 {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func potassium(f foods.Food) float64 {
+	if f.Micronutrients == nil {
+		return 0
+	}
+	return f.Micronutrients.Potassium
+}
+
+// DefaultSodiumMax is the WHO adult ceiling of 5 g salt a day.
+const DefaultSodiumMax = 2000
+
+const EnergyTolerance = 0.10
+
+type Request struct {
+	Targets      Targets
+	Restrictions foods.Restrictions
+	Version      models.Version
+}
+
+const (
+	roleBase      = "base"
+	roleProtein   = "protein"
+	roleVegetable = "vegetable"
+	roleFruit     = "fruit"
+)
+
+type slot struct {
+	role       string
+	categories []string
+}
+
+type mealTemplate struct {
+	name  string
+	key   string
+	time  string
+	share float64
+	slots []slot
+}
+
+var day = []mealTemplate{
+	{"Breakfast", "breakfast", "08:00", 0.25, []slot{
+		{roleBase, []string{"grain"}},
+		{roleProtein, []string{"dairy", "egg", "nut"}},
+		{roleFruit, []string{"fruit"}},
+	}},
+	{"Lunch", "lunch", "13:00", 0.35, []slot{
+		{roleBase, []string{"grain"}},
+		{roleProtein, []string{"legume", "meat", "fish", "dairy"}},
+		{roleVegetable, []string{"vegetable"}},
+	}},
+	{"Snack", "snack", "16:30", 0.10, []slot{
+		{roleFruit, []string{"fruit", "nut"}},
+	}},
+	{"Dinner", "dinner", "20:00", 0.30, []slot{
+		{roleBase, []string{"grain"}},
+		{roleProtein, []string{"legume", "meat", "fish", "egg", "dairy"}},
+		{roleVegetable, []string{"vegetable"}},
+	}},
+}
+
+// how many foods may be swapped out to bring the maximums down
+const maxSwaps = 10
+
+// portions stay between these multiples of a food's serving size
+const (
+	minServings = 0.5
+	maxServings = 3.0
+)
+
+type pick struct {
+	meal  int
+	slot  slot
+	food  foods.Food
+	grams float64
+}
+
+type candidate struct {
+	food  foods.Food
+	grade string
+}
+
+// Solve builds a day plan from the food table. It is deterministic: the same
+// request and table always give the same plan.
+func Solve(ix *foods.Index, req Request) (models.SolvedPlan, error) {
+	if req.Targets.EnergyKcal <= 0 {
+		return models.SolvedPlan{}, fmt.Errorf("energy target must be positive")
+	}
+	if req.Targets.SodiumMax <= 0 {
+		req.Targets.SodiumMax = DefaultSodiumMax
+	}
+
+	grades := map[string]string{}
+	for _, f := range ix.Foods() {
+		ns := cal.Calculate(f.Data(), models.Auto, req.Version)
+		grades[f.Name] = score.GetGrade(ns.Value, ns.ScoreType, ns.Version)
+	}
+
+	excluded := map[string]bool{}
+	picks, empty := choose(ix, req, grades, excluded)
+	balance(picks, req.Targets)
+
+	// while a maximum is over, drop the food contributing most to it and
+	// solve again. A swap only sticks if it lowers that nutrient without
+	// losing the energy or protein target, so picks is always the best plan
+	// seen so far.
+	for swaps := 0; swaps < maxSwaps; swaps++ {
+		l, over := exceeded(picks, req.Targets)
+		if !over {
+			break
+		}
+		swapped := false
+		for _, name := range byContribution(picks, l) {
+			excluded[name] = true
+			// a swap may not leave another slot without a food
+			next, nextEmpty := choose(ix, req, grades, excluded)
+			if len(nextEmpty) <= len(empty) {
+				balance(next, req.Targets)
+				if better(next, picks, l, req.Targets) {
+					picks, empty, swapped = next, nextEmpty, true
+					break
+				}
+			}
+			delete(excluded, name)
+		}
+		if !swapped {
+			break
+		}
+	}
+
+	return render(picks, empty, req.Targets, grades), nil
+}
+
+// choose fills every slot with the best-graded suitable food, preferring
+// foods not already on the day's menu. Slots no allowed food fits stay empty
+// and are returned as "role for meal", so the plan can say what's missing.
+func choose(ix *foods.Index, req Request, grades map[string]string, excluded map[string]bool) ([]pick, []string) {
+	used := map[string]int{}
+	var picks []pick
+	var empty []string
+
+	for mi, meal := range day {
+		for _, s := range meal.slots {
+			var cands []candidate
+			for _, f := range ix.Foods() {
+				if excluded[f.Name] || !slices.Contains(s.categories, f.Category) || len(req.Restrictions.Violations(f.AllTags())) > 0 {
+					continue
+				}
+				if len(f.Meals) > 0 && !slices.Contains(f.Meals, meal.key) {
+					continue
+				}
+				cands = append(cands, candidate{f, grades[f.Name]})
+			}
+			if len(cands) == 0 {
+				if s.role == roleBase || s.role == roleProtein {
+					empty = append(empty, s.role+" for "+meal.key)
+				}
+				continue
+			}
+
+			sort.SliceStable(cands, func(i, j int) bool {
+				a, b := cands[i], cands[j]
+				if ga, gb := gradeRank(a.grade), gradeRank(b.grade); ga != gb {
+					return ga < gb
+				}
+				if ua, ub := used[a.food.Name], used[b.food.Name]; ua != ub {
+					return ua < ub
+				}
+				if ma, mb := merit(s.role, a.food), merit(s.role, b.food); ma != mb {
+					return ma > mb
+				}
+				return a.food.Name < b.food.Name
+			})
+
+			f := cands[0].food
+			used[f.Name]++
+			picks = append(picks, pick{meal: mi, slot: s, food: f, grams: serving(f)})
+		}
+	}
+	return picks, empty
+}
+
+// A and B first, C only when nothing better fits, D and E last
+func gradeRank(g string) int {
+	switch g {
+	case "A", "B":
+		return 0
+	case "C":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// merit ranks foods within a grade: protein per kcal for the protein slot,
+// fibre per kcal for the base and fibre per serving for fruit and vegetables.
+func merit(role string, f foods.Food) float64 {
+	kcal := math.Max(f.Kcal, 1)
+	switch role {
+	case roleProtein:
+		return f.Protein / kcal
+	case roleBase:
+		return f.Fibre / kcal
+	default:
+		return f.Fibre * serving(f) / 100
+	}
+}
+
+func serving(f foods.Food) float64 {
+	if f.ServingGrams > 0 {
+		return f.ServingGrams
+	}
+	return 100
+}
+
+type totals struct {
+	kcal, protein, fibre, sodium    float64
+	potassium, sugars, carbohydrate float64
+}
+
+func sum(picks []pick, meal int) totals {
+	var t totals
+	for _, p := range picks {
+		if meal >= 0 && p.meal != meal {
+			continue
+		}
+		t.kcal += p.food.Kcal * p.grams / 100
+		t.protein += p.food.Protein * p.grams / 100
+		t.fibre += p.food.Fibre * p.grams / 100
+		t.sodium += p.food.Sodium * p.grams / 100
+		t.potassium += potassium(p.food) * p.grams / 100
+		t.sugars += p.food.Sugars * p.grams / 100
+		t.carbohydrate += p.food.Carbohydrate * p.grams / 100
+	}
+	return t
+}
+
+// exceeded returns the first maximum the plan goes over. Totals are compared
+// as whole units, as Checks reports them, so rounding portions to pieces
+// doesn't count as a miss.
+func exceeded(picks []pick, t Targets) (limit, bool) {
+	total := sum(picks, -1)
+	for _, l := range t.limits() {
+		if math.Round(l.total(total)) > l.max {
+			return l, true
+		}
+	}
+	return limit{}, false
+}
+
+func meets(t totals, tg Targets) (energy, protein bool) {
+	return math.Abs(t.kcal-tg.EnergyKcal) <= tg.EnergyKcal*EnergyTolerance, t.protein >= tg.Protein
+}
+
+// better reports whether next has less of l's nutrient than cur and still
+// meets every energy and protein target cur met.
+func better(next, cur []pick, l limit, tg Targets) bool {
+	n, c := sum(next, -1), sum(cur, -1)
+	if l.total(n) >= l.total(c) {
+		return false
+	}
+	ne, np := meets(n, tg)
+	ce, cp := meets(c, tg)
+	return (ne || !ce) && (np || !cp)
+}
+
+// byContribution lists the foods in the plan, largest contribution to l's
+// nutrient first.
+func byContribution(picks []pick, l limit) []string {
+	perFood := map[string]float64{}
+	for _, p := range picks {
+		perFood[p.food.Name] += l.per100g(p.food) * p.grams / 100
+	}
+	names := make([]string, 0, len(perFood))
+	for name := range perFood {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if perFood[names[i]] != perFood[names[j]] {
+			return perFood[names[i]] > perFood[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// headroom is how many more grams of f the day takes before one of the caps
+// is reached.
+func headroom(picks []pick, f foods.Food, t Targets) float64 {
+	total := sum(picks, -1)
+	room := math.Inf(1)
+	for _, l := range t.caps() {
+		if v := l.per100g(f); v > 0 {
+			room = math.Min(room, math.Max(0, l.max-l.total(total))/v*100)
+		}
+	}
+	return room
+}
+
+func grow(picks []pick, i int, by float64, t Targets) bool {
+	p := &picks[i]
+	add := math.Min(by*serving(p.food), serving(p.food)*maxServings-p.grams)
+	add = math.Min(add, headroom(picks, p.food, t))
+	if add < 1 {
+		return false
+	}
+	p.grams += add
+	return true
+}
+
+// balance nudges portions until protein and fibre are met and every meal
+// carries its share of the energy, or nothing more can move. Portions shrink
+// to get under a cap and never grow past one.
+func balance(picks []pick, t Targets) {
+	for iter := 0; iter < 40; iter++ {
+		changed := false
+		total := sum(picks, -1)
+
+		// over a cap, the food contributing most to it gives way first
+		for _, l := range t.caps() {
+			if math.Round(l.total(total)) <= l.max {
+				continue
+			}
+			biggest, most := -1, 0.0
+			for i, p := range picks {
+				if c := l.per100g(p.food) * p.grams; c > most && p.grams-serving(p.food)*minServings >= 1 {
+					biggest, most = i, c
+				}
+			}
+			if biggest >= 0 {
+				p := &picks[biggest]
+				p.grams = math.Max(serving(p.food)*minServings, p.grams-0.25*serving(p.food))
+				changed = true
+				total = sum(picks, -1)
+			}
+		}
+
+		if total.protein < t.Protein {
+			for i := range picks {
+				if picks[i].slot.role == roleProtein && grow(picks, i, 0.25, t) {
+					changed = true
+				}
+			}
+		}
+		if total.fibre < t.Fibre {
+			for i := range picks {
+				if r := picks[i].slot.role; (r == roleVegetable || r == roleFruit) && grow(picks, i, 0.25, t) {
+					changed = true
+				}
+			}
+		}
+
+		for mi, meal := range day {
+			want := meal.share * t.EnergyKcal
+			gap := want - sum(picks, mi).kcal
+			if math.Abs(gap) <= want*EnergyTolerance/2 {
+				continue
+			}
+			// the base food absorbs the difference first; whatever it
+			// can't take moves on to the other foods in the meal
+			for _, role := range []string{roleBase, roleProtein, roleFruit, roleVegetable} {
+				for i := range picks {
+					p := &picks[i]
+					if p.meal != mi || p.slot.role != role || p.food.Kcal <= 0 || math.Abs(gap) < 1 {
+						continue
+					}
+					grams := p.grams + gap/p.food.Kcal*100
+					grams = math.Max(serving(p.food)*minServings, math.Min(serving(p.food)*maxServings, grams))
+					if grams > p.grams {
+						grams = math.Min(grams, p.grams+headroom(picks, p.food, t))
+					}
+					if math.Abs(grams-p.grams) > 1 {
+						gap -= (grams - p.grams) * p.food.Kcal / 100
+						p.grams = grams
+						changed = true
+					}
+				}
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	for i := range picks {
+		picks[i].grams, _ = snap(picks[i].food, picks[i].grams)
+	}
+}
+
+func render(picks []pick, empty []string, t Targets, grades map[string]string) models.SolvedPlan {
+	out := models.SolvedPlan{Grades: map[string]string{}}
+
+	for mi, meal := range day {
+		pm := models.PlanMeal{Name: meal.name, Time: meal.time}
+		for i := range picks {
+			p := &picks[i]
+			if p.meal != mi {
+				continue
+			}
+			_, portion := snap(p.food, p.grams)
+			pm.Items = append(pm.Items, models.PlanItem{Food: p.food.Name, Portion: portion, Grams: p.grams})
+
+			factor := p.grams / 100
+			pm.Macros.EnergyKcal += p.food.Kcal * factor
+			pm.Macros.Protein += p.food.Protein * factor
+			pm.Macros.Carbohydrate += p.food.Carbohydrate * factor
+			pm.Macros.Fat += p.food.Fat * factor
+			pm.Macros.Fibre += p.food.Fibre * factor
+			out.Grades[p.food.Name] = grades[p.food.Name]
+		}
+		if len(pm.Items) == 0 {
+			continue
+		}
+		pm.Macros = roundMacros(pm.Macros)
+		out.Plan.Meals = append(out.Plan.Meals, pm)
+	}
+
+	got := sum(picks, -1)
+	energyMet, proteinMet := meets(got, t)
+	out.Sodium = math.Round(got.sodium)
+	out.Checks = []models.PlanCheck{
+		{Nutrient: "energy", Unit: "kcal", Kind: "approx", Target: t.EnergyKcal, Actual: math.Round(got.kcal),
+			Met: energyMet},
+		{Nutrient: "protein", Unit: "g", Kind: "min", Target: t.Protein, Actual: math.Round(got.protein), Met: proteinMet},
+		{Nutrient: "fibre", Unit: "g", Kind: "min", Target: t.Fibre, Actual: math.Round(got.fibre), Met: got.fibre >= t.Fibre},
+	}
+	for _, l := range t.limits() {
+		v := math.Round(l.total(got))
+		out.Checks = append(out.Checks, models.PlanCheck{Nutrient: l.nutrient, Unit: l.unit, Kind: "max", Target: l.max, Actual: v, Met: v <= l.max})
+	}
+
+	out.Plan.Notes = append(out.Plan.Notes, "Built from the local food table; portions are as eaten.")
+	for _, e := range empty {
+		out.Plan.Notes = append(out.Plan.Notes, fmt.Sprintf("No %s fits the dietary pattern and allergens.", e))
+	}
+	for _, c := range out.Checks {
+		if !c.Met {
+			out.Plan.Notes = append(out.Plan.Notes,
+				fmt.Sprintf("Could not meet the %s target (%.0f %s) with the allowed foods; the plan has %.0f %s.", c.Nutrient, c.Target, c.Unit, c.Actual, c.Unit))
+		}
+	}
+	return out
+}
+
+// snap rounds a portion to whole pieces where the food comes in pieces, and
+// to 5 g or ml otherwise.
+func snap(f foods.Food, grams float64) (float64, string) {
+	if f.PieceGrams >= 10 {
+		n := math.Max(1, math.Round(grams/f.PieceGrams))
+		unit := "piece"
+		if n > 1 {
+			unit = "pieces"
+		}
+		return n * f.PieceGrams, fmt.Sprintf("%.0f %s", n, unit)
+	}
+	grams = math.Max(5, math.Round(grams/5)*5)
+	if (f.IsBeverage || f.IsWater) && f.Density > 0 {
+		return grams, fmt.Sprintf("%.0f ml", grams/f.Density)
+	}
+	return grams, fmt.Sprintf("%.0f g", grams)
+}
+
+func roundMacros(m models.Macros) models.Macros {
+	return models.Macros{
+		EnergyKcal:   math.Round(m.EnergyKcal),
+		Protein:      math.Round(m.Protein*10) / 10,
+		Carbohydrate: math.Round(m.Carbohydrate*10) / 10,
+		Fat:          math.Round(m.Fat*10) / 10,
+		Fibre:        math.Round(m.Fibre*10) / 10,
+	}
+}
@@ -0,0 +1,142 @@
+package planner
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/models"
+)
+
+func solve(t *testing.T, req Request) models.SolvedPlan {
+	t.Helper()
+	ix, err := foods.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := Solve(ix, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plan
+}
+
+func TestSolveMeetsTargets(t *testing.T) {
+	req := Request{Targets: Targets{EnergyKcal: 2200, Protein: 70, Fibre: 30}, Version: models.V2017}
+	plan := solve(t, req)
+
+	for _, c := range plan.Checks {
+		if !c.Met {
+			t.Errorf("Expected the %s target to be met, got %v against %v", c.Nutrient, c.Actual, c.Target)
+		}
+	}
+	for food, grade := range plan.Grades {
+		if grade != "A" && grade != "B" {
+			t.Errorf("Expected only A/B foods, got %s graded %s", food, grade)
+		}
+	}
+	if len(plan.Plan.Meals) != 4 {
+		t.Errorf("Expected 4 meals, got %d", len(plan.Plan.Meals))
+	}
+
+	if again := solve(t, req); !reflect.DeepEqual(plan, again) {
+		t.Error("Expected the same plan for the same request")
+	}
+}
+
+func TestSolveRespectsDietAndAllergens(t *testing.T) {
+	ix, _ := foods.Embedded()
+	req := Request{
+		Targets:      Targets{EnergyKcal: 1800, Protein: 60, Fibre: 25, SodiumMax: 1500},
+		Restrictions: foods.Restrictions{Patterns: []string{foods.Vegan}, Allergens: []string{"gluten"}},
+		Version:      models.V2023,
+	}
+	plan := solve(t, req)
+
+	for _, m := range plan.Plan.Meals {
+		for _, it := range m.Items {
+			f, ok := ix.Lookup(it.Food)
+			if !ok || !f.Suits(foods.Vegan, []string{"gluten"}) {
+				t.Errorf("Expected only vegan gluten-free foods, got %s in %s", it.Food, m.Name)
+			}
+		}
+	}
+	if plan.Sodium > 1500 {
+		t.Errorf("Expected sodium within 1500 mg, got %v", plan.Sodium)
+	}
+}
+
+func TestSolveRejectsZeroEnergy(t *testing.T) {
+	ix, _ := foods.Embedded()
+	if _, err := Solve(ix, Request{}); err == nil {
+		t.Error("Expected an error without an energy target")
+	}
+}
+
+func TestSolveVegetarianHasNoEggs(t *testing.T) {
+	plan := solve(t, Request{
+		Targets:      Targets{EnergyKcal: 2200, Protein: 70, Fibre: 25, SodiumMax: 2000},
+		Restrictions: foods.Restrictions{Patterns: []string{foods.Vegetarian}},
+		Version:      models.V2023,
+	})
+	for _, m := range plan.Plan.Meals {
+		for _, it := range m.Items {
+			if it.Food == "egg" {
+				t.Errorf("Expected no egg for a vegetarian, got one in %s", m.Name)
+			}
+		}
+	}
+}
+
+func TestSodiumSwapsKeepEnergyAndProtein(t *testing.T) {
+	plan := solve(t, Request{
+		Targets:      Targets{EnergyKcal: 3000, Protein: 120, Fibre: 30, SodiumMax: 2000},
+		Restrictions: foods.Restrictions{Patterns: []string{foods.Vegan}},
+		Version:      models.V2023,
+	})
+	for _, c := range plan.Checks {
+		if (c.Nutrient == "energy" || c.Nutrient == "protein") && !c.Met {
+			t.Errorf("Expected swaps not to cost the %s target, got %v of %v", c.Nutrient, c.Actual, c.Target)
+		}
+	}
+}
+
+func TestSolveLeavesUnfillableSlotEmpty(t *testing.T) {
+	plan := solve(t, Request{
+		Targets:      Targets{EnergyKcal: 2000, Protein: 60, Fibre: 25, SodiumMax: 2000},
+		Restrictions: foods.Restrictions{Patterns: []string{foods.Vegan}, Allergens: []string{"peanut", "tree_nut"}},
+		Version:      models.V2023,
+	})
+	if len(plan.Plan.Meals) != 4 || len(plan.Plan.Meals[0].Items) != 2 {
+		t.Fatalf("Expected 4 meals with a 2-item breakfast, got %+v", plan.Plan.Meals)
+	}
+	if !slices.Contains(plan.Plan.Notes, "No protein for breakfast fits the dietary pattern and allergens.") {
+		t.Errorf("Expected a note about the missing breakfast protein, got %v", plan.Plan.Notes)
+	}
+}
+
+func TestSolveKeepsConditionMaximums(t *testing.T) {
+	// diabetes and CKD at 70 kg
+	plan := solve(t, Request{
+		Targets: Targets{EnergyKcal: 2000, Protein: 56, Fibre: 30, SodiumMax: 1500,
+			ProteinMax: 56, PotassiumMax: 2000, SugarsMax: 25, CarbohydrateMax: 180},
+		Version: models.V2023,
+	})
+
+	maxima := map[string]bool{}
+	for _, c := range plan.Checks {
+		if c.Kind != "max" {
+			continue
+		}
+		maxima[c.Nutrient] = true
+		if !c.Met {
+			t.Errorf("Expected %s within %v, got %v", c.Nutrient, c.Target, c.Actual)
+		}
+	}
+	for _, n := range []string{"sodium", "protein", "potassium", "sugars", "carbohydrate"} {
+		if !maxima[n] {
+			t.Errorf("Expected a %s maximum in the checks, got %+v", n, plan.Checks)
+		}
+	}
+}
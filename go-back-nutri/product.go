@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/off"
+	"github.com/MishraShardendu22/score"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+// product scores a packaged food from its Open Food Facts nutrition panel and
+// returns our grade next to the declared one. ?version= picks the algorithm
+// and defaults to 2023, the revision OFF declares its grades under; with 2017
+// the grades are returned but not compared.
+func product(c *fiber.Ctx) error {
+	code := c.Params("barcode")
+	if !off.ValidBarcode(code) {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "barcode must be 8 to 14 digits", nil, "")
+	}
+
+	v, ok := models.V2023, true
+	if q := c.Query("version"); q != "" {
+		v, ok = models.ParseVersion(q)
+	}
+	if !ok {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid algorithm version", nil, "")
+	}
+
+	if ProductSource.Index == nil && ProductSource.Mirror == nil {
+		return util.ResponseAPI(c, fiber.StatusServiceUnavailable, "no product database configured", nil, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	p, source, err := ProductSource.Lookup(ctx, code)
+	if errors.Is(err, off.ErrNotFound) {
+		return util.ResponseAPI(c, fiber.StatusNotFound, err.Error(), nil, "")
+	}
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadGateway, err.Error(), nil, "")
+	}
+
+	data := p.Data()
+	ns := cal.Calculate(data, models.Auto, v)
+	res := models.ProductScore{
+		Barcode:       code,
+		Name:          p.ProductName,
+		Brand:         p.Brands,
+		Source:        source,
+		Data:          data,
+		Score:         ns,
+		Grade:         score.GetGrade(ns.Value, ns.ScoreType, ns.Version),
+		DeclaredGrade: p.DeclaredGrade(),
+	}
+	if res.DeclaredGrade != "" {
+		res.DeclaredVersion = models.V2023
+		if v == res.DeclaredVersion {
+			matches := res.Grade == res.DeclaredGrade
+			res.Matches = &matches
+		}
+	}
+
+	return util.ResponseAPI(c, fiber.StatusOK, "product scored successfully", res, "")
+}
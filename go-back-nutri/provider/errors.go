@@ -0,0 +1,10 @@
+package provider
+
+import "errors"
+
+// Error kinds callers can match with errors.Is to pick an HTTP status.
+var (
+	ErrUnavailable = errors.New("llm upstream unavailable")
+	ErrRateLimited = errors.New("llm upstream rate limited")
+	ErrUnparseable = errors.New("llm output could not be parsed")
+)
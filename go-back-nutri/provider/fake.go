@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"strings"
+
+	"github.com/MishraShardendu22/models"
+)
+
+// Fake answers without a network call. The same input always gives the same
+// reply, so tests and offline deployments get stable grades.
+type Fake struct{}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) Model() string {
+	return "fake"
+}
+
+func (f *Fake) Complete(ctx context.Context, messages []Message) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	var system, user string
+	for _, m := range messages {
+		switch m.Role {
+		case "system":
+			system = m.Content
+		case "user":
+			user = m.Content
+		}
+	}
+
+	switch system {
+	case models.SystemGrade:
+		return fakeNutrients(user), nil
+	case models.SystemRephrasePlan:
+		// hand the plan back unchanged
+		return user, nil
+	}
+	return fakePlan, nil
+}
+
+// fakePlan only uses foods every dietary pattern and the common allergens
+// allow (no animal products, gluten, nuts, soy or root vegetables), so an
+// offline deployment can answer restricted requests too.
+const fakePlan = `{
+	"meals": [
+		{
+			"name": "Breakfast",
+			"time": "08:00",
+			"items": [
+				{"food": "idli", "portion": "3 pieces", "grams": 120},
+				{"food": "dal", "portion": "1 katori", "grams": 150},
+				{"food": "banana", "portion": "1 medium", "grams": 120}
+			],
+			"macros": {"energyKcal": 456, "protein": 20.4, "carbohydrate": 87, "fat": 4.6, "fibre": 16.8}
+		},
+		{
+			"name": "Lunch",
+			"time": "13:00",
+			"items": [
+				{"food": "rice", "portion": "1 katori", "grams": 150},
+				{"food": "rajma", "portion": "1 katori", "grams": 150},
+				{"food": "salad", "portion": "1 plate", "grams": 100}
+			],
+			"macros": {"energyKcal": 425, "protein": 16.3, "carbohydrate": 79.7, "fat": 4.2, "fibre": 12.2}
+		},
+		{
+			"name": "Dinner",
+			"time": "20:00",
+			"items": [
+				{"food": "brown rice", "portion": "1 katori", "grams": 150},
+				{"food": "chole", "portion": "1 katori", "grams": 150},
+				{"food": "spinach", "portion": "1 katori", "grams": 100}
+			],
+			"macros": {"energyKcal": 416, "protein": 17, "carbohydrate": 79.1, "fat": 8.5, "fibre": 13.9}
+		}
+	],
+	"notes": ["Drink water through the day."]
+}`
+
+// fakeNutrients emits one object per comma-separated item, with values
+// derived from a hash of the item name.
+func fakeNutrients(diet string) string {
+	var out []string
+	for _, name := range strings.Split(diet, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		h := fnv.New32a()
+		h.Write([]byte(name))
+		n := h.Sum32()
+
+		obj, _ := json.Marshal(map[string]any{
+			"Name":                name,
+			"Grams":               100,
+			"IsWater":             name == "water",
+			"IsBeverage":          false,
+			"IsCheese":            false,
+			"Energy":              float64(200 + n%1800),
+			"Sugars":              float64(n % 30),
+			"Fibre":               float64(n%60) / 10,
+			"Protein":             float64(n%150) / 10,
+			"Fruits":              float64(n % 100),
+			"Sodium":              float64(n % 700),
+			"SaturatedFattyAcids": float64(n%80) / 10,
+			"Fat":                 float64(n%80)/10 + float64(n%120)/10,
+			"Carbohydrate":        float64(n % 70),
+			"GlycaemicIndex":      float64(20 + n%70),
+		})
+		out = append(out, string(obj))
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MishraShardendu22/models"
+	"github.com/go-resty/resty/v2"
+)
+
+// OpenAI talks to any /chat/completions endpoint, OpenRouter included.
+type OpenAI struct {
+	cfg    Config
+	client *resty.Client
+}
+
+func NewOpenAI(cfg Config) *OpenAI {
+	return &OpenAI{
+		cfg:    cfg,
+		client: resty.New().SetTimeout(cfg.Timeout),
+	}
+}
+
+func (o *OpenAI) Model() string {
+	return o.cfg.Model
+}
+
+func (o *OpenAI) Complete(ctx context.Context, messages []Message) (string, error) {
+	backoff := o.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		content, err := o.complete(ctx, messages)
+		retryable := errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable)
+		if err == nil || !retryable || attempt >= o.cfg.Retries {
+			return content, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (o *OpenAI) complete(ctx context.Context, messages []Message) (string, error) {
+	payload := map[string]any{
+		"model":    o.cfg.Model,
+		"messages": messages,
+	}
+
+	req := o.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(payload)
+	if o.cfg.APIKey != "" {
+		req.SetHeader("Authorization", "Bearer "+o.cfg.APIKey)
+	}
+
+	res, err := req.Post(strings.TrimRight(o.cfg.BaseURL, "/") + "/chat/completions")
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	switch {
+	case res.StatusCode() == http.StatusTooManyRequests:
+		return "", fmt.Errorf("%w: %s", ErrRateLimited, res.String())
+	case res.StatusCode() >= 500:
+		return "", fmt.Errorf("%w: status %d: %s", ErrUnavailable, res.StatusCode(), res.String())
+	case res.IsError():
+		return "", fmt.Errorf("api error: %s", res.String())
+	}
+
+	var llmResp models.LLMResponse
+	if err := json.Unmarshal(res.Body(), &llmResp); err != nil {
+		return "", fmt.Errorf("%w: unmarshal response: %v", ErrUnparseable, err)
+	}
+	if len(llmResp.Choices) == 0 {
+		return "", fmt.Errorf("%w: no choices in response", ErrUnparseable)
+	}
+
+	return llmResp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOpenAIRetriesRateLimit(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	p, _ := New(Config{Kind: KindOpenAI, BaseURL: srv.URL, Model: "test", Retries: 2, Backoff: time.Millisecond})
+
+	content, err := p.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}})
+	if err != nil || content != "ok" {
+		t.Fatalf("Expected ok after a retry, got %q, %v", content, err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestOpenAIErrorKinds(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{http.StatusBadGateway, "", ErrUnavailable},
+		{http.StatusTooManyRequests, "", ErrRateLimited},
+		{http.StatusOK, `{"choices":[]}`, ErrUnparseable},
+	}
+
+	for _, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			w.Write([]byte(tc.body))
+		}))
+
+		p, _ := New(Config{Kind: KindOpenAI, BaseURL: srv.URL, Model: "test", Retries: 1, Backoff: time.Millisecond})
+		_, err := p.Complete(context.Background(), nil)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("Status %d: expected %v, got %v", tc.status, tc.want, err)
+		}
+		srv.Close()
+	}
+}
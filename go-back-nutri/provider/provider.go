@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Provider turns a chat completion request into the model's text reply.
+type Provider interface {
+	Complete(ctx context.Context, messages []Message) (string, error)
+	Model() string
+}
+
+// Streamer is implemented by providers that can hand out the reply as it is
+// generated. onDelta gets each new piece of text; returning an error from it
+// stops the stream. The full reply is returned at the end.
+type Streamer interface {
+	Stream(ctx context.Context, messages []Message, onDelta func(string) error) (string, error)
+}
+
+type Config struct {
+	Kind    string
+	BaseURL string
+	APIKey  string
+	Model   string
+	Timeout time.Duration
+	// extra attempts on 429/5xx, waiting Backoff and doubling it each time
+	Retries int
+	Backoff time.Duration
+}
+
+const (
+	OpenRouterURL  = "https://openrouter.ai/api/v1"
+	DefaultModel   = "deepseek/deepseek-chat-v3-0324:free"
+	DefaultTimeout = 60 * time.Second
+	DefaultRetries = 2
+	DefaultBackoff = 500 * time.Millisecond
+	KindOpenRouter = "openrouter"
+	KindOpenAI     = "openai"
+	KindFake       = "fake"
+)
+
+func New(cfg Config) (Provider, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Retries < 0 {
+		cfg.Retries = 0
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = DefaultBackoff
+	}
+
+	switch strings.ToLower(cfg.Kind) {
+	case "", KindOpenRouter:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("openrouter needs an api key")
+		}
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = OpenRouterURL
+		}
+		if cfg.Model == "" {
+			cfg.Model = DefaultModel
+		}
+		return NewOpenAI(cfg), nil
+	case KindOpenAI:
+		// any OpenAI-compatible server, e.g. a local llama.cpp or Ollama
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("openai-compatible provider needs a base url")
+		}
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("openai-compatible provider needs a model")
+		}
+		return NewOpenAI(cfg), nil
+	case KindFake:
+		return NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unknown llm provider %q", cfg.Kind)
+	}
+}
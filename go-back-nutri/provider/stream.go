@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Stream requests an SSE completion. Retries only happen before the first
+// token; once text has been handed out a failure is returned as is.
+func (o *OpenAI) Stream(ctx context.Context, messages []Message, onDelta func(string) error) (string, error) {
+	backoff := o.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		content, started, err := o.stream(ctx, messages, onDelta)
+		retryable := errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnavailable)
+		if err == nil || started || !retryable || attempt >= o.cfg.Retries {
+			return content, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+func (o *OpenAI) stream(ctx context.Context, messages []Message, onDelta func(string) error) (string, bool, error) {
+	payload := map[string]any{
+		"model":    o.cfg.Model,
+		"messages": messages,
+		"stream":   true,
+	}
+
+	// the client timeout would cut long streams off, so only ctx bounds it
+	req := o.client.Clone().SetTimeout(0).R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Accept", "text/event-stream").
+		SetBody(payload)
+	if o.cfg.APIKey != "" {
+		req.SetHeader("Authorization", "Bearer "+o.cfg.APIKey)
+	}
+
+	res, err := req.Post(strings.TrimRight(o.cfg.BaseURL, "/") + "/chat/completions")
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", false, ctx.Err()
+		}
+		return "", false, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	body := res.RawBody()
+	defer body.Close()
+
+	if res.StatusCode() >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(body, 4096))
+		switch {
+		case res.StatusCode() == http.StatusTooManyRequests:
+			return "", false, fmt.Errorf("%w: %s", ErrRateLimited, msg)
+		case res.StatusCode() >= 500:
+			return "", false, fmt.Errorf("%w: status %d: %s", ErrUnavailable, res.StatusCode(), msg)
+		default:
+			return "", false, fmt.Errorf("api error: %s", msg)
+		}
+	}
+
+	var full strings.Builder
+	sc := bufio.NewScanner(body)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		data, ok := strings.CutPrefix(sc.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil || len(chunk.Choices) == 0 {
+			continue
+		}
+		delta := chunk.Choices[0].Delta.Content
+		if delta == "" {
+			continue
+		}
+		full.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return full.String(), true, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		if ctx.Err() != nil {
+			return full.String(), full.Len() > 0, ctx.Err()
+		}
+		return full.String(), full.Len() > 0, fmt.Errorf("%w: reading stream: %v", ErrUnavailable, err)
+	}
+	if full.Len() == 0 {
+		return "", false, fmt.Errorf("%w: empty stream", ErrUnparseable)
+	}
+
+	return full.String(), true, nil
+}
+
+// Stream hands the canned reply out a few characters at a time.
+func (f *Fake) Stream(ctx context.Context, messages []Message, onDelta func(string) error) (string, error) {
+	content, err := f.Complete(ctx, messages)
+	if err != nil {
+		return "", err
+	}
+	for i := 0; i < len(content); i += fakeChunk {
+		if err := ctx.Err(); err != nil {
+			return content[:i], err
+		}
+		if err := onDelta(content[i:min(i+fakeChunk, len(content))]); err != nil {
+			return content[:i], err
+		}
+	}
+	return content, nil
+}
+
+const fakeChunk = 16
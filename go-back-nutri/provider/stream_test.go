@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenAIStream(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": keep-alive\n\n" +
+			"data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	p, _ := New(Config{Kind: KindOpenAI, BaseURL: srv.URL, Model: "test", Retries: 1, Backoff: time.Millisecond})
+
+	var deltas []string
+	content, err := p.(Streamer).Stream(context.Background(), []Message{{Role: "user", Content: "hi"}}, func(s string) error {
+		deltas = append(deltas, s)
+		return nil
+	})
+	if err != nil || content != "Hello" {
+		t.Fatalf("Expected Hello after a retry, got %q, %v", content, err)
+	}
+	if strings.Join(deltas, "|") != "Hel|lo" {
+		t.Errorf("Expected 2 deltas, got %v", deltas)
+	}
+}
+
+func TestFakeStreamStops(t *testing.T) {
+	stop := context.Canceled
+	var got strings.Builder
+	_, err := NewFake().Stream(context.Background(), []Message{{Role: "user", Content: "hi"}}, func(s string) error {
+		got.WriteString(s)
+		return stop
+	})
+	if err != stop || got.Len() != fakeChunk {
+		t.Errorf("Expected the stream to stop after one chunk, got %d bytes, %v", got.Len(), err)
+	}
+}
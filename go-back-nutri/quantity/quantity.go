@@ -0,0 +1,161 @@
+package quantity
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Unit string
+
+const (
+	Serving    Unit = "serving"
+	Piece      Unit = "piece"
+	Gram       Unit = "g"
+	Millilitre Unit = "ml"
+)
+
+// Quantity is an amount already reduced to one of the base units, so
+// "2 cups milk" is 480 ml of milk.
+type Quantity struct {
+	Amount float64
+	Unit   Unit
+	Food   string
+}
+
+// Portion is what a food needs for its quantities to become grams.
+type Portion struct {
+	ServingGrams float64
+	PieceGrams   float64
+	// g per ml, 1 when unknown
+	Density float64
+}
+
+type unitDef struct {
+	base   Unit
+	factor float64
+}
+
+var units = map[string]unitDef{
+	"g": {Gram, 1}, "gm": {Gram, 1}, "gms": {Gram, 1}, "gram": {Gram, 1}, "grams": {Gram, 1},
+	"kg": {Gram, 1000}, "kgs": {Gram, 1000}, "kilogram": {Gram, 1000}, "kilograms": {Gram, 1000},
+	"ml": {Millilitre, 1}, "millilitre": {Millilitre, 1}, "millilitres": {Millilitre, 1},
+	"milliliter": {Millilitre, 1}, "milliliters": {Millilitre, 1},
+	"l": {Millilitre, 1000}, "litre": {Millilitre, 1000}, "litres": {Millilitre, 1000},
+	"liter": {Millilitre, 1000}, "liters": {Millilitre, 1000},
+	"cup": {Millilitre, 240}, "cups": {Millilitre, 240},
+	"glass": {Millilitre, 250}, "glasses": {Millilitre, 250},
+	"tbsp": {Millilitre, 15}, "tbs": {Millilitre, 15}, "tablespoon": {Millilitre, 15}, "tablespoons": {Millilitre, 15},
+	"tsp": {Millilitre, 5}, "teaspoon": {Millilitre, 5}, "teaspoons": {Millilitre, 5},
+	"katori": {Millilitre, 150}, "katoris": {Millilitre, 150}, "bowl": {Millilitre, 150}, "bowls": {Millilitre, 150},
+	"piece": {Piece, 1}, "pieces": {Piece, 1}, "pc": {Piece, 1}, "pcs": {Piece, 1},
+	"slice": {Piece, 1}, "slices": {Piece, 1}, "nos": {Piece, 1},
+	"serving": {Serving, 1}, "servings": {Serving, 1}, "plate": {Serving, 1}, "plates": {Serving, 1},
+}
+
+var words = map[string]float64{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+	"half": 0.5, "quarter": 0.25,
+}
+
+var fractions = map[string]float64{"½": 0.5, "¼": 0.25, "¾": 0.75, "⅓": 1.0 / 3, "⅔": 2.0 / 3}
+
+// splits "150g" into "150 g" so the unit is its own token
+var glued = regexp.MustCompile(`^(\d+(?:\.\d+)?)([a-zA-Z]+)$`)
+
+// Parse reads a leading amount and unit from an item like "2 cups milk",
+// "150g rice", "half katori dal" or "2 rotis". Without an amount it is one
+// serving; an amount without a unit counts pieces.
+func Parse(item string) Quantity {
+	var tokens []string
+	for _, t := range strings.Fields(strings.ToLower(item)) {
+		if m := glued.FindStringSubmatch(t); m != nil {
+			tokens = append(tokens, m[1], m[2])
+			continue
+		}
+		tokens = append(tokens, t)
+	}
+
+	amount, found := 0.0, false
+	for len(tokens) > 0 {
+		// "half a cup" is half of one cup, not one and a half
+		if found && (tokens[0] == "a" || tokens[0] == "an") {
+			tokens = tokens[1:]
+			continue
+		}
+		v, ok := number(tokens[0])
+		if !ok {
+			break
+		}
+		amount += v
+		found = true
+		tokens = tokens[1:]
+	}
+	if !found {
+		amount = 1
+	}
+
+	unit := Serving
+	if found {
+		unit = Piece
+	}
+	if len(tokens) > 1 {
+		if def, ok := units[tokens[0]]; ok {
+			unit = def.base
+			amount *= def.factor
+			tokens = tokens[1:]
+			if len(tokens) > 1 && tokens[0] == "of" {
+				tokens = tokens[1:]
+			}
+		}
+	}
+
+	return Quantity{Amount: amount, Unit: unit, Food: strings.Join(tokens, " ")}
+}
+
+// number reads one amount token; "inf" and "nan" parse as floats but are no
+// amount anyone eats.
+func number(tok string) (float64, bool) {
+	if v, ok := words[tok]; ok {
+		return v, true
+	}
+	if v, ok := fractions[tok]; ok {
+		return v, true
+	}
+	if num, den, ok := strings.Cut(tok, "/"); ok {
+		n, err1 := strconv.ParseFloat(num, 64)
+		d, err2 := strconv.ParseFloat(den, 64)
+		if err1 != nil || err2 != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, n/d >= 0 && finite(n/d)
+	}
+	v, err := strconv.ParseFloat(tok, 64)
+	return v, err == nil && v >= 0 && finite(v)
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+func (q Quantity) Grams(p Portion) float64 {
+	switch q.Unit {
+	case Gram:
+		return q.Amount
+	case Millilitre:
+		density := p.Density
+		if density <= 0 {
+			density = 1
+		}
+		return q.Amount * density
+	case Piece:
+		if p.PieceGrams > 0 {
+			return q.Amount * p.PieceGrams
+		}
+		return q.Amount * p.ServingGrams
+	default:
+		return q.Amount * p.ServingGrams
+	}
+}
@@ -0,0 +1,36 @@
+package quantity
+
+import "testing"
+
+func TestParseAndConvert(t *testing.T) {
+	portion := Portion{ServingGrams: 150, PieceGrams: 40, Density: 1.03}
+
+	cases := []struct {
+		in    string
+		food  string
+		grams float64
+	}{
+		{"2 cups milk", "milk", 2 * 240 * 1.03},
+		{"150 g rice", "rice", 150},
+		{"150g rice", "rice", 150},
+		{"half katori dal", "dal", 75 * 1.03},
+		{"half a katori dal", "dal", 75 * 1.03},
+		{"half a cup milk", "milk", 120 * 1.03},
+		{"inf milk", "inf milk", 150},
+		{"1 1/2 tbsp ghee", "ghee", 22.5 * 1.03},
+		{"2 rotis", "rotis", 80},
+		{"3 pieces of roti", "roti", 120},
+		{"orange", "orange", 150},
+		{"0.5 kg chicken", "chicken", 500},
+	}
+
+	for _, tc := range cases {
+		q := Parse(tc.in)
+		if q.Food != tc.food {
+			t.Errorf("Parse(%q): expected food %q, got %q", tc.in, tc.food, q.Food)
+		}
+		if g := q.Grams(portion); g < tc.grams-0.001 || g > tc.grams+0.001 {
+			t.Errorf("Parse(%q): expected %.2f g, got %.2f", tc.in, tc.grams, g)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/scheme"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type rateRequest struct {
+	calcRequest
+	Schemes      []string
+	PortionGrams float64
+}
+
+// rate runs one or several front-of-pack schemes over the same product. The
+// schemes come from the Schemes body field or a comma-separated ?schemes=
+// query, e.g. ?schemes=nutriscore,traffic-light,health-star.
+func rate(c *fiber.Ctx) error {
+	var req rateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid request data", nil, "")
+	}
+	st, v, err := cal.Validate(req.NutritionalData, req.ScoreType, req.Version, models.V2017)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	names := req.Schemes
+	if q := c.Query("schemes"); q != "" {
+		names = strings.Split(q, ",")
+	}
+	schemes, err := scheme.Parse(names)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	if req.PortionGrams < 0 {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "portion grams cannot be negative", nil, "")
+	}
+
+	opts := scheme.Options{ScoreType: st, Version: v, PortionGrams: req.PortionGrams}
+	return util.ResponseAPI(c, fiber.StatusOK, "product rated successfully", scheme.RateAll(req.NutritionalData, opts, schemes), "")
+}
@@ -0,0 +1,110 @@
+package rda
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/MishraShardendu22/models"
+)
+
+// Profile picks the reference intake row.
+type Profile struct {
+	Age      int
+	Gender   string
+	Pregnant bool
+}
+
+const (
+	ScopeDay  = "day"
+	ScopeMeal = "meal"
+)
+
+// a meal is judged against a third of the daily intake
+const MealShare = 1.0 / 3
+
+// below this share of the target an intake is flagged as a deficit
+const DeficitBelow = 0.7
+
+type nutrient struct {
+	name string
+	unit string
+	get  func(models.Micronutrients) float64
+}
+
+var nutrients = []nutrient{
+	{"iron", "mg", func(m models.Micronutrients) float64 { return m.Iron }},
+	{"calcium", "mg", func(m models.Micronutrients) float64 { return m.Calcium }},
+	{"vitaminA", "µg", func(m models.Micronutrients) float64 { return m.VitaminA }},
+	{"vitaminC", "mg", func(m models.Micronutrients) float64 { return m.VitaminC }},
+	{"vitaminD", "µg", func(m models.Micronutrients) float64 { return m.VitaminD }},
+	{"vitaminB12", "µg", func(m models.Micronutrients) float64 { return m.VitaminB12 }},
+	{"folate", "µg", func(m models.Micronutrients) float64 { return m.Folate }},
+	{"potassium", "mg", func(m models.Micronutrients) float64 { return m.Potassium }},
+	{"zinc", "mg", func(m models.Micronutrients) float64 { return m.Zinc }},
+}
+
+func (p Profile) Validate() error {
+	if p.Age < 1 || p.Age > 120 {
+		return fmt.Errorf("age must be between 1 and 120")
+	}
+	switch strings.ToLower(p.Gender) {
+	case "male", "female", "other":
+	default:
+		return fmt.Errorf("gender must be male, female or other")
+	}
+	if p.Pregnant && (strings.ToLower(p.Gender) == "male" || p.Age < 14 || p.Age > 50) {
+		return fmt.Errorf("pregnancy intakes cover females aged 14 to 50")
+	}
+	return nil
+}
+
+// Analyse compares an amount eaten with the reference intakes. Coverage is
+// the share of the meal's weight that had micronutrient data at all.
+func Analyse(amount models.Micronutrients, coverage float64, p Profile, scope string) models.MicronutrientAnalysis {
+	group, ref := Lookup(p)
+
+	share := 1.0
+	if scope == ScopeMeal {
+		share = MealShare
+	} else {
+		scope = ScopeDay
+	}
+
+	out := models.MicronutrientAnalysis{Scope: scope, Group: group, Coverage: round(coverage)}
+	for _, n := range nutrients {
+		got, rda := n.get(amount), n.get(ref.RDA)
+		target := rda * share
+
+		status := models.MicronutrientStatus{
+			Nutrient:   n.name,
+			Unit:       n.unit,
+			Amount:     round(got),
+			RDA:        rda,
+			Target:     round(target),
+			PercentRDA: round(got / rda * 100),
+			Status:     "ok",
+		}
+
+		if ul := n.get(ref.UL); ul > 0 {
+			status.UpperLimit = &ul
+			// upper limits are daily, so a meal only needs its own share
+			if got > ul*share {
+				status.Status = "excess"
+				out.Excesses = append(out.Excesses, n.name)
+			}
+		}
+		if status.Status == "ok" && got < target*DeficitBelow {
+			status.Status = "deficit"
+			out.Deficits = append(out.Deficits, n.name)
+		}
+
+		out.Nutrients = append(out.Nutrients, status)
+	}
+
+	return out
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
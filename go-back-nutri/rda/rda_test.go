@@ -0,0 +1,39 @@
+package rda
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/MishraShardendu22/models"
+)
+
+func TestAnalyse(t *testing.T) {
+	day := models.Micronutrients{
+		Iron: 9, Calcium: 1100, VitaminA: 4000, VitaminC: 80, VitaminD: 5,
+		VitaminB12: 2.5, Folate: 400, Potassium: 2700, Zinc: 45,
+	}
+
+	got := Analyse(day, 1, Profile{Age: 30, Gender: "female"}, ScopeDay)
+
+	if got.Group != "female 19-50" {
+		t.Errorf("Expected female 19-50, got %s", got.Group)
+	}
+	// 9 of 18 mg iron and 5 of 15 µg vitamin D are under 70%
+	if !slices.Equal(got.Deficits, []string{"iron", "vitaminD"}) {
+		t.Errorf("Expected iron and vitamin D deficits, got %v", got.Deficits)
+	}
+	// 4000 µg RAE has no limit since most of it is usually carotenoids
+	if !slices.Equal(got.Excesses, []string{"zinc"}) {
+		t.Errorf("Expected only a zinc excess, got %v", got.Excesses)
+	}
+
+	pregnant := Analyse(day, 1, Profile{Age: 30, Gender: "female", Pregnant: true}, ScopeDay)
+	if !slices.Contains(pregnant.Deficits, "folate") {
+		t.Errorf("Expected folate to fall short in pregnancy, got %v", pregnant.Deficits)
+	}
+
+	meal := Analyse(day.Scale(MealShare), 1, Profile{Age: 30, Gender: "male"}, ScopeMeal)
+	if len(meal.Deficits) != 1 || meal.Deficits[0] != "vitaminD" {
+		t.Errorf("Expected only vitamin D short for the meal, got %v", meal.Deficits)
+	}
+}
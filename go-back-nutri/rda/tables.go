@@ -0,0 +1,112 @@
+package rda
+
+import (
+	"strings"
+
+	"github.com/MishraShardendu22/models"
+)
+
+// Reference holds the daily recommended intake and the tolerable upper limit.
+// A zero upper limit means none is set. Vitamin A and folate have none here:
+// their limits only cover preformed retinol and synthetic folic acid, while
+// the table tracks total RAE and DFE, so carrots or dal would read as excess.
+type Reference struct {
+	RDA models.Micronutrients
+	UL  models.Micronutrients
+}
+
+type row struct {
+	group  string
+	minAge int
+	maxAge int
+	male   models.Micronutrients
+	female models.Micronutrients
+	ul     models.Micronutrients
+}
+
+// Dietary Reference Intakes (US National Academies); potassium uses the
+// adequate intake. Order is iron, calcium, A, C, D, B12, folate, K, zinc.
+var table = []row{
+	{"1-3", 1, 3,
+		models.Micronutrients{Iron: 7, Calcium: 700, VitaminA: 300, VitaminC: 15, VitaminD: 15, VitaminB12: 0.9, Folate: 150, Potassium: 2000, Zinc: 3},
+		models.Micronutrients{Iron: 7, Calcium: 700, VitaminA: 300, VitaminC: 15, VitaminD: 15, VitaminB12: 0.9, Folate: 150, Potassium: 2000, Zinc: 3},
+		models.Micronutrients{Iron: 40, Calcium: 2500, VitaminC: 400, VitaminD: 63, Zinc: 7}},
+	{"4-8", 4, 8,
+		models.Micronutrients{Iron: 10, Calcium: 1000, VitaminA: 400, VitaminC: 25, VitaminD: 15, VitaminB12: 1.2, Folate: 200, Potassium: 2300, Zinc: 5},
+		models.Micronutrients{Iron: 10, Calcium: 1000, VitaminA: 400, VitaminC: 25, VitaminD: 15, VitaminB12: 1.2, Folate: 200, Potassium: 2300, Zinc: 5},
+		models.Micronutrients{Iron: 40, Calcium: 2500, VitaminC: 650, VitaminD: 75, Zinc: 12}},
+	{"9-13", 9, 13,
+		models.Micronutrients{Iron: 8, Calcium: 1300, VitaminA: 600, VitaminC: 45, VitaminD: 15, VitaminB12: 1.8, Folate: 300, Potassium: 2500, Zinc: 8},
+		models.Micronutrients{Iron: 8, Calcium: 1300, VitaminA: 600, VitaminC: 45, VitaminD: 15, VitaminB12: 1.8, Folate: 300, Potassium: 2300, Zinc: 8},
+		models.Micronutrients{Iron: 40, Calcium: 3000, VitaminC: 1200, VitaminD: 100, Zinc: 23}},
+	{"14-18", 14, 18,
+		models.Micronutrients{Iron: 11, Calcium: 1300, VitaminA: 900, VitaminC: 75, VitaminD: 15, VitaminB12: 2.4, Folate: 400, Potassium: 3000, Zinc: 11},
+		models.Micronutrients{Iron: 15, Calcium: 1300, VitaminA: 700, VitaminC: 65, VitaminD: 15, VitaminB12: 2.4, Folate: 400, Potassium: 2300, Zinc: 9},
+		models.Micronutrients{Iron: 45, Calcium: 3000, VitaminC: 1800, VitaminD: 100, Zinc: 34}},
+	{"19-50", 19, 50,
+		models.Micronutrients{Iron: 8, Calcium: 1000, VitaminA: 900, VitaminC: 90, VitaminD: 15, VitaminB12: 2.4, Folate: 400, Potassium: 3400, Zinc: 11},
+		models.Micronutrients{Iron: 18, Calcium: 1000, VitaminA: 700, VitaminC: 75, VitaminD: 15, VitaminB12: 2.4, Folate: 400, Potassium: 2600, Zinc: 8},
+		models.Micronutrients{Iron: 45, Calcium: 2500, VitaminC: 2000, VitaminD: 100, Zinc: 40}},
+	{"51-70", 51, 70,
+		models.Micronutrients{Iron: 8, Calcium: 1000, VitaminA: 900, VitaminC: 90, VitaminD: 15, VitaminB12: 2.4, Folate: 400, Potassium: 3400, Zinc: 11},
+		models.Micronutrients{Iron: 8, Calcium: 1200, VitaminA: 700, VitaminC: 75, VitaminD: 15, VitaminB12: 2.4, Folate: 400, Potassium: 2600, Zinc: 8},
+		models.Micronutrients{Iron: 45, Calcium: 2000, VitaminC: 2000, VitaminD: 100, Zinc: 40}},
+	{"71+", 71, 200,
+		models.Micronutrients{Iron: 8, Calcium: 1200, VitaminA: 900, VitaminC: 90, VitaminD: 20, VitaminB12: 2.4, Folate: 400, Potassium: 3400, Zinc: 11},
+		models.Micronutrients{Iron: 8, Calcium: 1200, VitaminA: 700, VitaminC: 75, VitaminD: 20, VitaminB12: 2.4, Folate: 400, Potassium: 2600, Zinc: 8},
+		models.Micronutrients{Iron: 45, Calcium: 2000, VitaminC: 2000, VitaminD: 100, Zinc: 40}},
+}
+
+var pregnancyTeen = Reference{
+	RDA: models.Micronutrients{Iron: 27, Calcium: 1300, VitaminA: 750, VitaminC: 80, VitaminD: 15, VitaminB12: 2.6, Folate: 600, Potassium: 2600, Zinc: 12},
+	UL:  models.Micronutrients{Iron: 45, Calcium: 3000, VitaminC: 1800, VitaminD: 100, Zinc: 34},
+}
+
+var pregnancyAdult = Reference{
+	RDA: models.Micronutrients{Iron: 27, Calcium: 1000, VitaminA: 770, VitaminC: 85, VitaminD: 15, VitaminB12: 2.6, Folate: 600, Potassium: 2900, Zinc: 11},
+	UL:  models.Micronutrients{Iron: 45, Calcium: 2500, VitaminC: 2000, VitaminD: 100, Zinc: 40},
+}
+
+// Lookup returns the group label and intakes for a profile. "other" takes the
+// higher of the male and female value for each nutrient so nobody is
+// under-served.
+func Lookup(p Profile) (string, Reference) {
+	if p.Pregnant {
+		if p.Age <= 18 {
+			return "pregnancy 14-18", pregnancyTeen
+		}
+		return "pregnancy 19-50", pregnancyAdult
+	}
+
+	r := table[len(table)-1]
+	for _, candidate := range table {
+		if p.Age >= candidate.minAge && p.Age <= candidate.maxAge {
+			r = candidate
+			break
+		}
+	}
+
+	gender := strings.ToLower(p.Gender)
+	switch gender {
+	case "male":
+		return "male " + r.group, Reference{RDA: r.male, UL: r.ul}
+	case "female":
+		return "female " + r.group, Reference{RDA: r.female, UL: r.ul}
+	default:
+		return r.group, Reference{RDA: higher(r.male, r.female), UL: r.ul}
+	}
+}
+
+func higher(a, b models.Micronutrients) models.Micronutrients {
+	return models.Micronutrients{
+		Iron:       max(a.Iron, b.Iron),
+		Calcium:    max(a.Calcium, b.Calcium),
+		VitaminA:   max(a.VitaminA, b.VitaminA),
+		VitaminC:   max(a.VitaminC, b.VitaminC),
+		VitaminD:   max(a.VitaminD, b.VitaminD),
+		VitaminB12: max(a.VitaminB12, b.VitaminB12),
+		Folate:     max(a.Folate, b.Folate),
+		Potassium:  max(a.Potassium, b.Potassium),
+		Zinc:       max(a.Zinc, b.Zinc),
+	}
+}
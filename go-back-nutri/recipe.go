@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/recipe"
+	"github.com/MishraShardendu22/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type recipeIngredient struct {
+	Name     string                  `json:"name"`
+	Grams    float64                 `json:"grams"`
+	Category string                  `json:"category"`
+	Data     *models.NutritionalData `json:"data"`
+}
+
+type recipeRequest struct {
+	Name        string             `json:"name"`
+	Ingredients []recipeIngredient `json:"ingredients"`
+	WaterLoss   float64            `json:"waterLoss"`
+	ScoreType   string             `json:"scoreType"`
+	Version     string             `json:"version"`
+}
+
+func recipeScore(c *fiber.Ctx) error {
+	var req recipeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, "invalid request data", nil, "")
+	}
+
+	r := recipe.Recipe{Name: req.Name, WaterLoss: req.WaterLoss}
+	for _, in := range req.Ingredients {
+		r.Ingredients = append(r.Ingredients, recipe.Ingredient{
+			Name:     in.Name,
+			Grams:    in.Grams,
+			Category: in.Category,
+			Data:     in.Data,
+		})
+	}
+	if err := r.Validate(); err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	st, v, err := cal.ParseOptions(req.ScoreType, req.Version, models.V2017)
+	if err != nil {
+		return util.ResponseAPI(c, fiber.StatusBadRequest, err.Error(), nil, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+
+	ingredients, err := recipe.Resolve(ctx, LLMProvider, FoodIndex, r)
+	if err != nil {
+		return util.ResponseAPI(c, llmErrorStatus(err), err.Error(), nil, "")
+	}
+
+	result := recipe.Score(r.Name, ingredients, r.WaterLoss, st, v)
+	return util.ResponseAPI(c, fiber.StatusOK, "recipe scored successfully", result, "")
+}
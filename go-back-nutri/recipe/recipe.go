@@ -0,0 +1,160 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/provider"
+	"github.com/MishraShardendu22/score"
+	"github.com/MishraShardendu22/util"
+)
+
+type Ingredient struct {
+	Name     string
+	Grams    float64
+	Category string
+	// per-100g values supplied by the kitchen, skips the lookup when set
+	Data *models.NutritionalData
+}
+
+type Recipe struct {
+	Name        string
+	Ingredients []Ingredient
+	// percent of the raw weight lost as water while cooking
+	WaterLoss float64
+}
+
+func (r Recipe) Validate() error {
+	if len(r.Ingredients) == 0 {
+		return fmt.Errorf("recipe needs at least one ingredient")
+	}
+	for _, in := range r.Ingredients {
+		if strings.TrimSpace(in.Name) == "" {
+			return fmt.Errorf("ingredient name cannot be empty")
+		}
+		if in.Grams <= 0 {
+			return fmt.Errorf("ingredient %q needs a positive weight", in.Name)
+		}
+		if in.Data != nil {
+			if _, _, err := cal.Validate(*in.Data, "", "", models.V2017); err != nil {
+				return fmt.Errorf("ingredient %q: %w", in.Name, err)
+			}
+		}
+	}
+	if r.WaterLoss < 0 || r.WaterLoss >= 100 {
+		return fmt.Errorf("water loss must be between 0 and 100 percent")
+	}
+	return nil
+}
+
+// Resolve fills in every ingredient's per-100g nutrients: from the request,
+// then the food table, and only the rest from the LLM in one call.
+func Resolve(ctx context.Context, p provider.Provider, ix *foods.Index, r Recipe) ([]models.RecipeIngredient, error) {
+	resolved := make([]models.RecipeIngredient, len(r.Ingredients))
+	var unknown []int
+
+	for i, in := range r.Ingredients {
+		resolved[i] = models.RecipeIngredient{Name: in.Name, Grams: in.Grams, Category: in.Category}
+
+		if in.Data != nil {
+			resolved[i].Data = *in.Data
+			resolved[i].Source = models.SourceRequest
+			continue
+		}
+
+		f, ok := ix.Lookup(in.Name)
+		if !ok {
+			unknown = append(unknown, i)
+			continue
+		}
+		resolved[i].Data = f.Data()
+		resolved[i].Source = models.SourceDatabase
+		if resolved[i].Category == "" {
+			resolved[i].Category = f.Category
+		}
+	}
+
+	if len(unknown) == 0 {
+		return resolved, nil
+	}
+
+	names := make([]string, len(unknown))
+	for j, i := range unknown {
+		names[j] = r.Ingredients[i].Name
+	}
+
+	estimated, err := util.LLM(ctx, p, strings.Join(names, ", "))
+	if err != nil {
+		return nil, err
+	}
+
+	// match the model's items back by name only: the model may skip an
+	// ingredient, so their order says nothing
+	byName := map[string]models.FoodItem{}
+	for _, item := range estimated {
+		byName[foods.Normalise(item.Name)] = item
+	}
+	for _, i := range unknown {
+		item, ok := byName[foods.Normalise(r.Ingredients[i].Name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: no estimate for ingredient %q", provider.ErrUnparseable, r.Ingredients[i].Name)
+		}
+		resolved[i].Data = item.Data
+		resolved[i].Source = models.SourceLLM
+	}
+
+	return resolved, nil
+}
+
+// Score computes the cooked dish's per-100g profile and grades it. Nutrients
+// are summed from the raw weights and spread over the cooked weight; the
+// fruit/veg/legume/nut share comes from ingredient categories, or from an
+// uncategorised ingredient's own Fruits share, as the LLM estimates give.
+func Score(name string, ingredients []models.RecipeIngredient, waterLoss float64, st models.ScoreType, v models.Version) models.RecipeScore {
+	items := make([]models.FoodItem, len(ingredients))
+	var fruitVegGrams float64
+	for i, in := range ingredients {
+		items[i] = models.FoodItem{Name: in.Name, Grams: in.Grams, Data: in.Data, Source: in.Source}
+		switch {
+		case foods.CountsAsFruitVeg(in.Category):
+			fruitVegGrams += in.Grams
+		case in.Category == "":
+			fruitVegGrams += in.Grams * min(100, float64(in.Data.Fruits)) / 100
+		}
+	}
+
+	meal := cal.Combine(items)
+	cooked := meal.Grams * (1 - waterLoss/100)
+	concentrate := meal.Grams / cooked
+
+	per100g := meal.Per100g
+	per100g.Energy = models.EnergyKJ(float64(per100g.Energy) * concentrate)
+	per100g.Sugars = models.SugarGram(float64(per100g.Sugars) * concentrate)
+	per100g.Fibre = models.FibreGram(float64(per100g.Fibre) * concentrate)
+	per100g.Protein = models.ProteinGram(float64(per100g.Protein) * concentrate)
+	per100g.Sodium = models.SodiumMilligram(float64(per100g.Sodium) * concentrate)
+	per100g.SaturatedFattyAcids = models.SaturatedFattyAcidsGram(float64(per100g.SaturatedFattyAcids) * concentrate)
+	per100g.Fat = models.FatGram(float64(per100g.Fat) * concentrate)
+	per100g.Carbohydrate = models.CarbohydrateGram(float64(per100g.Carbohydrate) * concentrate)
+	if m := per100g.Micronutrients; m != nil {
+		concentrated := m.Scale(concentrate)
+		per100g.Micronutrients = &concentrated
+	}
+	per100g.Fruits = models.FruitsPercent(min(100, fruitVegGrams/meal.Grams*100))
+
+	ns := cal.Calculate(per100g, st, v)
+
+	return models.RecipeScore{
+		Name:        name,
+		RawGrams:    meal.Grams,
+		CookedGrams: cooked,
+		Per100g:     per100g,
+		Ingredients: ingredients,
+		Score:       ns,
+		Grade:       score.GetGrade(ns.Value, ns.ScoreType, ns.Version),
+	}
+}
@@ -0,0 +1,95 @@
+package recipe
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MishraShardendu22/foods"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/provider"
+)
+
+func TestScoreUsesCategoriesAndWaterLoss(t *testing.T) {
+	ix, err := foods.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := Recipe{
+		Name: "palak dal",
+		Ingredients: []Ingredient{
+			{Name: "dal", Grams: 200},
+			{Name: "spinach", Grams: 100},
+			{Name: "rice", Grams: 100},
+			{Name: "house spice mix", Grams: 0.5, Category: "other", Data: &models.NutritionalData{Energy: 1000}},
+		},
+		WaterLoss: 20,
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	ingredients, err := Resolve(context.Background(), provider.NewFake(), ix, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ingredients[3].Source != models.SourceRequest || ingredients[0].Category != "legume" {
+		t.Errorf("Expected request data and table categories to be kept, got %+v", ingredients)
+	}
+
+	result := Score(r.Name, ingredients, r.WaterLoss, models.Food, models.V2017)
+
+	if want := 300 / 400.5 * 100; float64(result.Per100g.Fruits) < want-0.01 || float64(result.Per100g.Fruits) > want+0.01 {
+		t.Errorf("Expected fruit/veg share %.2f, got %.2f", want, result.Per100g.Fruits)
+	}
+	if c := result.CookedGrams; c < 320.39 || c > 320.41 {
+		t.Errorf("Expected cooked weight %.1f, got %.1f", 400.5*0.8, result.CookedGrams)
+	}
+	// 200g dal at 9g protein plus spinach and rice, spread over 320.4g cooked
+	want := (18 + 2.9 + 2.7) / 320.4 * 100
+	if p := float64(result.Per100g.Protein); p < want-0.01 || p > want+0.01 {
+		t.Errorf("Expected %.2f g protein per 100 g, got %.2f", want, p)
+	}
+}
+
+func TestScoreUsesFruitsShareWithoutCategory(t *testing.T) {
+	ingredients := []models.RecipeIngredient{
+		{Name: "tomato gravy", Grams: 100, Source: models.SourceLLM, Data: models.NutritionalData{Energy: 200, Fruits: 80}},
+		{Name: "paneer", Grams: 100, Category: "dairy", Source: models.SourceDatabase, Data: models.NutritionalData{Energy: 1200, Fruits: 50}},
+	}
+
+	// the gravy counts 80 g, the categorised paneer nothing
+	result := Score("paneer masala", ingredients, 0, models.Food, models.V2017)
+	if f := float64(result.Per100g.Fruits); f < 39.99 || f > 40.01 {
+		t.Errorf("Expected fruit/veg share 40, got %.2f", f)
+	}
+}
+
+// skipping leaves out every ingredient but saffron
+type skipping struct{}
+
+func (skipping) Complete(ctx context.Context, messages []provider.Message) (string, error) {
+	return `[{"Name": "saffron", "Grams": 100, "Energy": 1300}]`, nil
+}
+
+func (skipping) Model() string { return "skipping" }
+
+func TestResolveMatchesEstimatesByName(t *testing.T) {
+	ix, err := foods.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := Recipe{Name: "pulao", Ingredients: []Ingredient{{Name: "saffron", Grams: 1}, {Name: "kewra water", Grams: 5}}}
+	if _, err := Resolve(context.Background(), skipping{}, ix, r); !errors.Is(err, provider.ErrUnparseable) {
+		t.Errorf("Expected a skipped ingredient to be an error, got %v", err)
+	}
+}
+
+func TestValidateChecksIngredientData(t *testing.T) {
+	r := Recipe{Ingredients: []Ingredient{{Name: "stock", Grams: 100, Data: &models.NutritionalData{Sodium: -1}}}}
+	if err := r.Validate(); err == nil {
+		t.Error("Expected negative ingredient data to be rejected")
+	}
+}
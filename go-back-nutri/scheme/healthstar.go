@@ -0,0 +1,53 @@
+package scheme
+
+import (
+	"strconv"
+
+	"github.com/MishraShardendu22/constant"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/util"
+)
+
+// HealthStar is the Australian/NZ Health Star Rating. Every product is rated
+// on the category 2 (general food) table except plain water, which always
+// gets five stars.
+type HealthStar struct{}
+
+func (HealthStar) Name() string { return NameHealthStar }
+
+func (HealthStar) Rate(data models.NutritionalData, opts Options) models.Rating {
+	if data.IsWater {
+		hsr := models.HealthStarRating{Stars: 5}
+		return models.Rating{Scheme: NameHealthStar, Label: stars(hsr.Stars), Detail: hsr}
+	}
+
+	baseline := util.GetPointsFromRange(float64(data.Energy), constant.HSREnergyLevels) +
+		util.GetPointsFromRange(float64(data.SaturatedFattyAcids), constant.HSRSaturatedFattyAcidsLevels) +
+		util.GetPointsFromRange(float64(data.Sugars), constant.HSRSugarsLevels) +
+		util.GetPointsFromRange(float64(data.Sodium), constant.HSRSodiumLevels)
+
+	hsr := models.HealthStarRating{
+		Baseline:     baseline,
+		FruitsPoints: util.GetPointsFromRange(float64(data.Fruits), constant.HSRFruitsLevels),
+		FibrePoints:  util.GetPointsFromRange(float64(data.Fibre), constant.HSRFibreLevels),
+	}
+	if baseline < constant.HSRProteinCutOff || hsr.FruitsPoints >= constant.HSRFruitsExemptPoints {
+		hsr.ProteinCounted = true
+		hsr.ProteinPoints = util.GetPointsFromRange(float64(data.Protein), constant.HSRProteinLevels)
+	}
+	hsr.Score = baseline - hsr.FruitsPoints - hsr.ProteinPoints - hsr.FibrePoints
+
+	hsr.Stars = constant.HSRStars[len(constant.HSRStars)-1]
+	for i, bound := range constant.HSRStarBounds {
+		if hsr.Score <= bound {
+			hsr.Stars = constant.HSRStars[i]
+			break
+		}
+	}
+
+	return models.Rating{Scheme: NameHealthStar, Label: stars(hsr.Stars), Detail: hsr}
+}
+
+func stars(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64) + " stars"
+}
@@ -0,0 +1,22 @@
+package scheme
+
+import (
+	"github.com/MishraShardendu22/cal"
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/score"
+)
+
+// NutriScore wraps the existing points calculation.
+type NutriScore struct{}
+
+func (NutriScore) Name() string { return NameNutriScore }
+
+func (NutriScore) Rate(data models.NutritionalData, opts Options) models.Rating {
+	ns := cal.Calculate(data, opts.ScoreType, opts.Version)
+	grade := score.GetGrade(ns.Value, ns.ScoreType, ns.Version)
+	return models.Rating{
+		Scheme: NameNutriScore,
+		Label:  grade,
+		Detail: models.NutriScoreRating{Grade: grade, Score: ns},
+	}
+}
@@ -0,0 +1,81 @@
+package scheme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MishraShardendu22/models"
+)
+
+// Options carries what a scheme may need beyond the per-100g values.
+// PortionGrams is optional; the traffic lights use it for the portion rule.
+// ScoreType models.Auto infers the Nutri-Score type from the product flags.
+type Options struct {
+	ScoreType    models.ScoreType
+	Version      models.Version
+	PortionGrams float64
+}
+
+// Scheme is one front-of-pack rating system.
+type Scheme interface {
+	Name() string
+	Rate(data models.NutritionalData, opts Options) models.Rating
+}
+
+const (
+	NameNutriScore   = "nutriscore"
+	NameTrafficLight = "traffic-light"
+	NameHealthStar   = "health-star"
+)
+
+var schemes = []Scheme{NutriScore{}, TrafficLight{}, HealthStar{}}
+
+// Names lists every scheme in the order they are reported.
+func Names() []string {
+	out := make([]string, len(schemes))
+	for i, s := range schemes {
+		out[i] = s.Name()
+	}
+	return out
+}
+
+func Get(name string) (Scheme, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, s := range schemes {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// Parse resolves the requested names, dropping duplicates. An empty list
+// means Nutri-Score only, as before schemes could be chosen.
+func Parse(names []string) ([]Scheme, error) {
+	if len(names) == 0 {
+		return []Scheme{NutriScore{}}, nil
+	}
+
+	seen := map[string]bool{}
+	out := make([]Scheme, 0, len(names))
+	for _, name := range names {
+		s, ok := Get(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown scheme %q, expected one of %s", name, strings.Join(Names(), ", "))
+		}
+		if !seen[s.Name()] {
+			seen[s.Name()] = true
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// RateAll runs every scheme over the same product.
+func RateAll(data models.NutritionalData, opts Options, schemes []Scheme) []models.Rating {
+	out := make([]models.Rating, 0, len(schemes))
+	for _, s := range schemes {
+		out = append(out, s.Rate(data, opts))
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package scheme
+
+import (
+	"testing"
+
+	"github.com/MishraShardendu22/models"
+)
+
+var chocolate = models.NutritionalData{
+	Energy: 2240, Sugars: 48, Fibre: 7, Protein: 7.7,
+	Sodium: 24, SaturatedFattyAcids: 18.5, Fat: 30,
+}
+
+func TestTrafficLight(t *testing.T) {
+	got := TrafficLight{}.Rate(chocolate, Options{})
+	if got.Label != "3 red, 0 amber, 1 green" {
+		t.Errorf("Expected 3 red and 1 green, got %s", got.Label)
+	}
+
+	// 10.6 g sugars per 100 ml is amber, but a 330 ml can carries 39% of
+	// the reference intake
+	cola := models.NutritionalData{IsBeverage: true, Energy: 180, Sugars: 10.6, Sodium: 4}
+	lights := TrafficLight{}.Rate(cola, Options{PortionGrams: 330}).Detail.(models.TrafficLights)
+	if lights.Basis != "100ml" || lights.Lights[2].Nutrient != "sugars" || lights.Lights[2].Colour != Red {
+		t.Errorf("Expected red sugars per portion, got %+v", lights)
+	}
+}
+
+func TestHealthStar(t *testing.T) {
+	got := HealthStar{}.Rate(chocolate, Options{}).Detail.(models.HealthStarRating)
+	// 6 + 18 + 10 + 0 baseline, protein past the cut-off, 7 fibre points
+	if got.Baseline != 34 || got.ProteinCounted || got.Score != 27 || got.Stars != 0.5 {
+		t.Errorf("Expected 0.5 stars from a score of 27, got %+v", got)
+	}
+
+	apple := models.NutritionalData{Energy: 218, Sugars: 10.4, Fibre: 2.4, Protein: 0.3, Fruits: 100, Sodium: 1}
+	got = HealthStar{}.Rate(apple, Options{}).Detail.(models.HealthStarRating)
+	if got.Score != -8 || got.Stars != 4.5 {
+		t.Errorf("Expected 4.5 stars from a score of -8, got %+v", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	got, err := Parse(nil)
+	if err != nil || len(got) != 1 || got[0].Name() != NameNutriScore {
+		t.Errorf("Expected Nutri-Score by default, got %v %v", got, err)
+	}
+
+	got, err = Parse([]string{"health-star", " Traffic-Light", "health-star"})
+	if err != nil || len(got) != 2 || got[0].Name() != NameHealthStar || got[1].Name() != NameTrafficLight {
+		t.Errorf("Expected health-star and traffic-light, got %v %v", got, err)
+	}
+
+	if _, err := Parse([]string{"nova"}); err == nil {
+		t.Error("Expected an error for an unknown scheme")
+	}
+
+	ratings := RateAll(chocolate, Options{ScoreType: models.Food, Version: models.V2023}, []Scheme{NutriScore{}})
+	if ratings[0].Label != "E" {
+		t.Errorf("Expected Nutri-Score E for chocolate, got %s", ratings[0].Label)
+	}
+}
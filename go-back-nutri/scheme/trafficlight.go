@@ -0,0 +1,101 @@
+package scheme
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/MishraShardendu22/constant"
+	"github.com/MishraShardendu22/models"
+)
+
+// TrafficLight is the UK FSA multiple traffic light label for fat, saturates,
+// sugars and salt.
+type TrafficLight struct{}
+
+const (
+	Green = "green"
+	Amber = "amber"
+	Red   = "red"
+)
+
+func (TrafficLight) Name() string { return NameTrafficLight }
+
+func (TrafficLight) Rate(data models.NutritionalData, opts Options) models.Rating {
+	drink := data.IsBeverage || data.IsWater
+	bounds := map[string][2]float64{
+		"fat":       constant.TrafficFat,
+		"saturates": constant.TrafficSaturates,
+		"sugars":    constant.TrafficSugars,
+		"salt":      constant.TrafficSalt,
+	}
+	basis, portionLimit, portionHigh := "100g", float64(constant.TrafficPortionFood), constant.TrafficPortionHigh
+	if drink {
+		bounds = map[string][2]float64{
+			"fat":       constant.TrafficFatDrink,
+			"saturates": constant.TrafficSaturatesDrink,
+			"sugars":    constant.TrafficSugarsDrink,
+			"salt":      constant.TrafficSaltDrink,
+		}
+		basis, portionLimit, portionHigh = "100ml", constant.TrafficPortionDrink, constant.TrafficPortionHighDrink
+	}
+
+	// saturates are part of total fat, so a missing fat value can't be lower
+	values := []struct {
+		name string
+		v    float64
+	}{
+		{"fat", math.Max(float64(data.Fat), float64(data.SaturatedFattyAcids))},
+		{"saturates", float64(data.SaturatedFattyAcids)},
+		{"sugars", float64(data.Sugars)},
+		{"salt", float64(data.Sodium) * 2.5 / 1000},
+	}
+
+	out := models.TrafficLights{Basis: basis, PortionGrams: opts.PortionGrams}
+	reds, ambers := 0, 0
+	for _, n := range values {
+		light := models.TrafficLight{Nutrient: n.name, Per100g: round2(n.v), Colour: colour(n.v, bounds[n.name])}
+
+		if opts.PortionGrams > 0 {
+			portion := n.v * opts.PortionGrams / 100
+			pct := portion / constant.ReferenceIntakes[n.name] * 100
+			light.PerPortion = ptr(round2(portion))
+			light.PercentRI = ptr(round2(pct))
+			if opts.PortionGrams > portionLimit && pct > portionHigh*100 {
+				light.Colour = Red
+			}
+		}
+
+		switch light.Colour {
+		case Red:
+			reds++
+		case Amber:
+			ambers++
+		}
+		out.Lights = append(out.Lights, light)
+	}
+
+	return models.Rating{
+		Scheme: NameTrafficLight,
+		Label:  fmt.Sprintf("%d red, %d amber, %d green", reds, ambers, len(values)-reds-ambers),
+		Detail: out,
+	}
+}
+
+func colour(v float64, b [2]float64) string {
+	switch {
+	case v <= b[0]:
+		return Green
+	case v > b[1]:
+		return Red
+	default:
+		return Amber
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
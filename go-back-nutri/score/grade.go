@@ -1,18 +1,19 @@
 package score
 
-import "github.com/MishraShardendu22/constant"
+import (
+	"github.com/MishraShardendu22/constant"
+	"github.com/MishraShardendu22/models"
+)
 
-func GetGrade(score int) string {
-	switch {
-	case score <= -1:
+func GetGrade(score int, st models.ScoreType, v models.Version) string {
+	if st == models.Water {
 		return constant.ScoreToLetter[0] // A
-	case score <= 2:
-		return constant.ScoreToLetter[1] // B
-	case score <= 10:
-		return constant.ScoreToLetter[2] // C
-	case score <= 18:
-		return constant.ScoreToLetter[3] // D
-	default:
-		return constant.ScoreToLetter[4] // E
 	}
+
+	for i, bound := range Levels(v, st).GradeBounds {
+		if score <= bound {
+			return constant.ScoreToLetter[i]
+		}
+	}
+	return constant.ScoreToLetter[4] // E
 }
@@ -0,0 +1,22 @@
+package score
+
+import (
+	"github.com/MishraShardendu22/constant"
+	"github.com/MishraShardendu22/models"
+)
+
+// Levels picks the threshold tables for a version and score type. Cheese and
+// water share the food tables; water is graded A regardless of its points.
+func Levels(v models.Version, st models.ScoreType) constant.Levels {
+	beverage := st == models.Beverage
+	switch {
+	case v == models.V2023 && beverage:
+		return constant.Beverage2023
+	case v == models.V2023:
+		return constant.Food2023
+	case beverage:
+		return constant.Beverage2017
+	default:
+		return constant.Food2017
+	}
+}
@@ -1,9 +1,8 @@
 package score
 
 import (
-	"github.com/MishraShardendu22/util"
 	"github.com/MishraShardendu22/models"
-	"github.com/MishraShardendu22/constant"
+	"github.com/MishraShardendu22/util"
 )
 
 type Energy models.EnergyKJ
@@ -14,64 +13,43 @@ type Fruits models.FruitsPercent
 type Sodium models.SodiumMilligram
 type SaturatedFattyAcids models.SaturatedFattyAcidsGram
 
-func (e Energy) GetPoints(st models.ScoreType) int {
+func (e Energy) GetPoints(st models.ScoreType, v models.Version) int {
 	val := models.EnergyKJ(e)
-	if st == models.Beverage {
-		return util.GetPointsFromRange(float64(val), constant.EnergyLevelsBeverage)
-	}
-	return util.GetPointsFromRange(float64(val), constant.EnergyLevels)
+	return util.GetPointsFromRange(float64(val), Levels(v, st).Energy)
 }
 
-func (s Sugar) GetPoints(st models.ScoreType) int {
+func (s Sugar) GetPoints(st models.ScoreType, v models.Version) int {
 	val := models.SugarGram(s)
-	if st == models.Beverage {
-		return util.GetPointsFromRange(float64(val), constant.SugarsLevelsBeverage)
-	}
-	return util.GetPointsFromRange(float64(val), constant.SugarsLevels)
+	return util.GetPointsFromRange(float64(val), Levels(v, st).Sugars)
 }
 
-func (sfa SaturatedFattyAcids) GetPoints(st models.ScoreType) int {
+func (sfa SaturatedFattyAcids) GetPoints(st models.ScoreType, v models.Version) int {
 	val := models.SaturatedFattyAcidsGram(sfa)
-	return util.GetPointsFromRange(float64(val), constant.SaturatedFattyAcidsLevels)
+	return util.GetPointsFromRange(float64(val), Levels(v, st).SaturatedFattyAcids)
 }
 
-func (s Sodium) GetPoints(st models.ScoreType) int {
+func (s Sodium) GetPoints(st models.ScoreType, v models.Version) int {
 	val := models.SodiumMilligram(s)
-	return util.GetPointsFromRange(float64(val), constant.SodiumLevels)
+	return util.GetPointsFromRange(float64(val), Levels(v, st).Sodium)
 }
 
-func (f Fruits) GetPoints(st models.ScoreType) int {
+func (f Fruits) GetPoints(st models.ScoreType, v models.Version) int {
 	val := models.FruitsPercent(f)
-	if st == models.Beverage {
-		switch {
-		case val > 80:
-			return 10
-		case val > 60:
-			return 4
-		case val > 40:
-			return 2
-		default:
-			return 0
+	levels := Levels(v, st)
+	for i, l := range levels.Fruits {
+		if float64(val) > l {
+			return levels.FruitsPoints[i]
 		}
 	}
-	switch {
-	case val > 80:
-		return 5
-	case val > 60:
-		return 2
-	case val > 40:
-		return 1
-	default:
-		return 0
-	}
+	return 0
 }
 
-func (f Fibre) GetPoints(st models.ScoreType) int {
+func (f Fibre) GetPoints(st models.ScoreType, v models.Version) int {
 	val := models.FibreGram(f)
-	return util.GetPointsFromRange(float64(val), constant.FibreLevels)
+	return util.GetPointsFromRange(float64(val), Levels(v, st).Fibre)
 }
 
-func (p Protein) GetPoints(st models.ScoreType) int {
+func (p Protein) GetPoints(st models.ScoreType, v models.Version) int {
 	val := models.ProteinGram(p)
-	return util.GetPointsFromRange(float64(val), constant.ProteinLevels)
+	return util.GetPointsFromRange(float64(val), Levels(v, st).Protein)
 }
@@ -1,59 +1,213 @@
 package util
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strconv"
+	"regexp"
+	"strings"
 
+	"github.com/MishraShardendu22/cache"
 	"github.com/MishraShardendu22/models"
-	"github.com/go-resty/resty/v2"
+	"github.com/MishraShardendu22/provider"
 )
 
-func TT(height int, weight int, bloodGroup string, gender string, openAI_API string) (any, error) {
-	userPayload := `{"height":` + strconv.Itoa(height) +
-		`,"weight":` + strconv.Itoa(weight) +
-		`,"bloodGroup":"` + bloodGroup + `","gender":"` + gender + `"}`
+var planTime = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
 
-	messages := []map[string]any{
-		{"role": "system", "content": models.SystemDietPlan},
-		{"role": "user", "content": userPayload},
+// ErrRestricted is returned when the plan still breaks the request's
+// dietary patterns or allergens after the repair pass.
+var ErrRestricted = errors.New("plan breaks the dietary restrictions")
+
+// PlanChecker rejects a plan that parses but breaks the request, such as a
+// peanut for a peanut allergy. Its errors should wrap ErrRestricted.
+type PlanChecker func(plan models.DietPlan) error
+
+// TT asks for a structured plan. Output that fails validation or check gets
+// one repair pass with the error before we give up. check is required when
+// the request has patterns or allergens. Valid plans are cached by the
+// request, the model and the prompt version.
+func TT(ctx context.Context, p provider.Provider, req models.PlanRequest, check PlanChecker) (models.DietPlan, error) {
+	return TTStream(ctx, p, req, check, PlanStream{})
+}
+
+// PlanStream receives the raw plan text while it is generated. Reset is
+// called before the repair pass, when the text sent so far is void. A nil
+// Token means no streaming.
+type PlanStream struct {
+	Token func(text string) error
+	Reset func() error
+}
+
+// TTStream is TT with the model output handed to s as it arrives. Providers
+// that can't stream send the whole reply as one token. Cached plans send no
+// tokens at all.
+func TTStream(ctx context.Context, p provider.Provider, req models.PlanRequest, check PlanChecker, s PlanStream) (models.DietPlan, error) {
+	if check == nil && (len(req.Patterns) > 0 || len(req.Allergens) > 0) {
+		return models.DietPlan{}, fmt.Errorf("plan request has dietary restrictions but no checker")
 	}
 
-	payload := map[string]any{
-		"model":    "deepseek/deepseek-chat-v3-0324:free",
-		"messages": messages,
+	userPayload, err := json.Marshal(req)
+	if err != nil {
+		return models.DietPlan{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	key := cache.Key(cache.KindPlan, p.Model(), cache.PromptVersion(models.SystemDietPlan), strings.ToLower(string(userPayload)))
+	return cache.Do(ctx, LLMCache, cache.KindPlan, key, func() (models.DietPlan, error) {
+		return tt(ctx, p, check, userPayload, s)
+	})
+}
+
+func tt(ctx context.Context, p provider.Provider, check PlanChecker, userPayload []byte, s PlanStream) (models.DietPlan, error) {
+	messages := []provider.Message{
+		{Role: "system", Content: models.SystemDietPlan},
+		{Role: "user", Content: string(userPayload)},
 	}
 
-	jsonData, err := json.Marshal(payload)
+	content, err := complete(ctx, p, messages, s.Token)
 	if err != nil {
-		return nil, fmt.Errorf("marshal payload: %w", err)
+		return models.DietPlan{}, fmt.Errorf("llm error: %w", err)
+	}
+
+	plan, err := parseAndCheck(check, content)
+	if err == nil {
+		return plan, nil
 	}
 
-	client := resty.New()
-	res, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Authorization", "Bearer "+openAI_API).
-		SetBody(jsonData).
-		Post("https://openrouter.ai/api/v1/chat/completions")
+	messages = append(messages,
+		provider.Message{Role: "assistant", Content: content},
+		provider.Message{Role: "user", Content: "That output is invalid: " + err.Error() + ". Return the corrected JSON object only."},
+	)
 
+	if s.Reset != nil {
+		if err := s.Reset(); err != nil {
+			return models.DietPlan{}, err
+		}
+	}
+	content, err = complete(ctx, p, messages, s.Token)
 	if err != nil {
-		return nil, fmt.Errorf("request error: %w", err)
+		return models.DietPlan{}, fmt.Errorf("llm error: %w", err)
+	}
+
+	return parseAndCheck(check, content)
+}
+
+func parseAndCheck(check PlanChecker, content string) (models.DietPlan, error) {
+	plan, err := ParseDietPlan(content)
+	if err != nil || check == nil {
+		return plan, err
 	}
+	return plan, check(plan)
+}
 
-	if res.IsError() {
-		return nil, fmt.Errorf("api error: %s", res.String())
+func complete(ctx context.Context, p provider.Provider, messages []provider.Message, onToken func(string) error) (string, error) {
+	if onToken == nil {
+		return p.Complete(ctx, messages)
+	}
+	if sp, ok := p.(provider.Streamer); ok {
+		return sp.Stream(ctx, messages, onToken)
 	}
 
-	var parsed map[string]any
-	if err := json.Unmarshal(res.Body(), &parsed); err != nil {
-		return nil, fmt.Errorf("unmarshal response: %w", err)
+	content, err := p.Complete(ctx, messages)
+	if err != nil {
+		return "", err
 	}
+	return content, onToken(content)
+}
+
+// ParseDietPlan strictly decodes and validates a plan from model output,
+// tolerating only markdown fences or stray text around the JSON object.
+func ParseDietPlan(content string) (models.DietPlan, error) {
+	var plan models.DietPlan
 
-	choices := parsed["choices"].([]any)
-	first := choices[0].(map[string]any)
-	message := first["message"].(map[string]any)
-	content := message["content"].(string)
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return plan, fmt.Errorf("%w: no JSON object in diet plan", provider.ErrUnparseable)
+	}
 
-	return content, nil
+	dec := json.NewDecoder(bytes.NewReader([]byte(content[start : end+1])))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&plan); err != nil {
+		return plan, fmt.Errorf("%w: %v", provider.ErrUnparseable, err)
+	}
+
+	if err := ValidateDietPlan(plan); err != nil {
+		return plan, fmt.Errorf("%w: %v", provider.ErrUnparseable, err)
+	}
+
+	return plan, nil
+}
+
+func ValidateDietPlan(plan models.DietPlan) error {
+	if len(plan.Meals) == 0 {
+		return fmt.Errorf("plan has no meals")
+	}
+
+	for i, m := range plan.Meals {
+		if strings.TrimSpace(m.Name) == "" {
+			return fmt.Errorf("meal %d has no name", i)
+		}
+		if !planTime.MatchString(m.Time) {
+			return fmt.Errorf("meal %q has invalid time %q, want HH:MM", m.Name, m.Time)
+		}
+		if len(m.Items) == 0 {
+			return fmt.Errorf("meal %q has no items", m.Name)
+		}
+		for _, it := range m.Items {
+			if strings.TrimSpace(it.Food) == "" || strings.TrimSpace(it.Portion) == "" {
+				return fmt.Errorf("meal %q has an item without food or portion", m.Name)
+			}
+			if it.Grams <= 0 {
+				return fmt.Errorf("%q in meal %q needs positive grams", it.Food, m.Name)
+			}
+		}
+		mc := m.Macros
+		if mc.EnergyKcal < 0 || mc.Protein < 0 || mc.Carbohydrate < 0 || mc.Fat < 0 || mc.Fibre < 0 {
+			return fmt.Errorf("meal %q has negative macros", m.Name)
+		}
+	}
+
+	return nil
+}
+
+// RephrasePlan lets the LLM reword portions and notes of a computed plan. The
+// foods, grams, times and macros must come back unchanged, so the plan still
+// meets the targets it was solved for.
+func RephrasePlan(ctx context.Context, p provider.Provider, plan models.DietPlan) (models.DietPlan, error) {
+	payload, err := json.Marshal(plan)
+	if err != nil {
+		return plan, fmt.Errorf("marshal plan: %w", err)
+	}
+
+	content, err := p.Complete(ctx, []provider.Message{
+		{Role: "system", Content: models.SystemRephrasePlan},
+		{Role: "user", Content: string(payload)},
+	})
+	if err != nil {
+		return plan, fmt.Errorf("llm error: %w", err)
+	}
+
+	out, err := ParseDietPlan(content)
+	if err != nil {
+		return plan, err
+	}
+
+	if len(out.Meals) != len(plan.Meals) {
+		return plan, fmt.Errorf("%w: rephrased plan has %d meals, want %d", provider.ErrUnparseable, len(out.Meals), len(plan.Meals))
+	}
+	for i, m := range plan.Meals {
+		got := out.Meals[i]
+		if got.Name != m.Name || got.Time != m.Time || len(got.Items) != len(m.Items) {
+			return plan, fmt.Errorf("%w: rephrased plan changed meal %q", provider.ErrUnparseable, m.Name)
+		}
+		for j, it := range m.Items {
+			if got.Items[j].Food != it.Food || got.Items[j].Grams != it.Grams {
+				return plan, fmt.Errorf("%w: rephrased plan changed %q in meal %q", provider.ErrUnparseable, it.Food, m.Name)
+			}
+		}
+		out.Meals[i].Macros = m.Macros
+	}
 
+	return out, nil
 }
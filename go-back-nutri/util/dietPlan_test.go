@@ -0,0 +1,130 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/MishraShardendu22/models"
+	"github.com/MishraShardendu22/provider"
+)
+
+var planRequest = models.PlanRequest{Height: 170, Weight: 65, BloodGroup: "O+", Gender: "female"}
+
+type scripted struct {
+	replies []string
+	calls   int
+}
+
+func (s *scripted) Model() string { return "scripted" }
+
+func (s *scripted) Complete(ctx context.Context, messages []provider.Message) (string, error) {
+	reply := s.replies[s.calls]
+	s.calls++
+	return reply, nil
+}
+
+func TestTTRepairsInvalidPlan(t *testing.T) {
+	valid := `{"meals":[{"name":"Lunch","time":"13:00","items":[{"food":"rice","portion":"1 katori","grams":150}],"macros":{"energyKcal":200,"protein":4,"carbohydrate":44,"fat":0.5,"fibre":1}}],"notes":[]}`
+	p := &scripted{replies: []string{`{"meals":[{"name":"Lunch","time":"lunchtime"}]}`, "```json\n" + valid + "\n```"}}
+
+	plan, err := TT(context.Background(), p, planRequest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.calls != 2 || len(plan.Meals) != 1 || plan.Meals[0].Items[0].Food != "rice" {
+		t.Errorf("Expected the repaired plan after 2 calls, got %+v after %d", plan, p.calls)
+	}
+}
+
+func TestTTGivesUpAfterOneRepair(t *testing.T) {
+	p := &scripted{replies: []string{"Eat more vegetables.", `{"meals":[],"notes":[]}`}}
+
+	_, err := TT(context.Background(), p, planRequest, nil)
+	if !errors.Is(err, provider.ErrUnparseable) {
+		t.Errorf("Expected ErrUnparseable, got %v", err)
+	}
+}
+
+func TestRephrasePlanKeepsFoodsAndGrams(t *testing.T) {
+	solved, _ := ParseDietPlan(`{"meals":[{"name":"Lunch","time":"13:00","items":[{"food":"rice","portion":"150 g","grams":150}],"macros":{"energyKcal":195,"protein":4,"carbohydrate":42,"fat":0.5,"fibre":0.6}}],"notes":["Built from the local food table."]}`)
+
+	reworded := `{"meals":[{"name":"Lunch","time":"13:00","items":[{"food":"rice","portion":"1 katori","grams":150}],"macros":{"energyKcal":999,"protein":4,"carbohydrate":42,"fat":0.5,"fibre":0.6}}],"notes":["Eat slowly."]}`
+	plan, err := RephrasePlan(context.Background(), &scripted{replies: []string{reworded}}, solved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Meals[0].Items[0].Portion != "1 katori" || plan.Meals[0].Macros.EnergyKcal != 195 {
+		t.Errorf("Expected the new portion with the solved macros, got %+v", plan.Meals[0])
+	}
+
+	changed := `{"meals":[{"name":"Lunch","time":"13:00","items":[{"food":"rice","portion":"2 katori","grams":300}],"macros":{"energyKcal":390,"protein":8,"carbohydrate":84,"fat":1,"fibre":1.2}}],"notes":[]}`
+	if _, err := RephrasePlan(context.Background(), &scripted{replies: []string{changed}}, solved); !errors.Is(err, provider.ErrUnparseable) {
+		t.Errorf("Expected ErrUnparseable when grams change, got %v", err)
+	}
+}
+
+func TestTTStreamSendsTokensAndResets(t *testing.T) {
+	var text strings.Builder
+	resets := 0
+	plan, err := TTStream(context.Background(), provider.NewFake(), planRequest, nil, PlanStream{
+		Token: func(s string) error { text.WriteString(s); return nil },
+		Reset: func() error { resets++; return nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resets != 0 || len(plan.Meals) != 3 {
+		t.Errorf("Expected a 3 meal plan without a reset, got %d meals and %d resets", len(plan.Meals), resets)
+	}
+	if _, err := ParseDietPlan(text.String()); err != nil {
+		t.Errorf("Expected the streamed text to be the plan, got %v", err)
+	}
+
+	// scripted can't stream, so each reply arrives as a single token
+	valid := `{"meals":[{"name":"Lunch","time":"13:00","items":[{"food":"rice","portion":"1 katori","grams":150}],"macros":{"energyKcal":200,"protein":4,"carbohydrate":44,"fat":0.5,"fibre":1}}],"notes":[]}`
+	var tokens []string
+	_, err = TTStream(context.Background(), &scripted{replies: []string{"not a plan", valid}}, planRequest, nil, PlanStream{
+		Token: func(s string) error { tokens = append(tokens, s); return nil },
+		Reset: func() error { tokens = nil; return nil },
+	})
+	if err != nil || len(tokens) != 1 || tokens[0] != valid {
+		t.Errorf("Expected only the repaired reply after the reset, got %q, %v", tokens, err)
+	}
+}
+
+func TestTTRegeneratesPlanThatBreaksRestrictions(t *testing.T) {
+	check := func(plan models.DietPlan) error {
+		for _, m := range plan.Meals {
+			for _, it := range m.Items {
+				if it.Food == "chicken" {
+					return fmt.Errorf("%w: chicken is not vegetarian", ErrRestricted)
+				}
+			}
+		}
+		return nil
+	}
+
+	meal := func(food string) string {
+		return `{"meals":[{"name":"Lunch","time":"13:00","items":[{"food":"` + food + `","portion":"1 katori","grams":150}],"macros":{"energyKcal":200,"protein":20,"carbohydrate":4,"fat":5,"fibre":1}}],"notes":[]}`
+	}
+	req := planRequest
+	req.Patterns = []string{"vegetarian"}
+
+	p := &scripted{replies: []string{meal("chicken"), meal("paneer")}}
+	plan, err := TT(context.Background(), p, req, check)
+	if err != nil || p.calls != 2 || plan.Meals[0].Items[0].Food != "paneer" {
+		t.Errorf("Expected the regenerated paneer plan after 2 calls, got %+v %v after %d", plan, err, p.calls)
+	}
+
+	p = &scripted{replies: []string{meal("chicken"), meal("chicken")}}
+	if _, err := TT(context.Background(), p, req, check); !errors.Is(err, ErrRestricted) {
+		t.Errorf("Expected ErrRestricted when the repair still breaks the pattern, got %v", err)
+	}
+
+	if _, err := TT(context.Background(), &scripted{replies: []string{meal("paneer")}}, req, nil); err == nil {
+		t.Error("Expected an error for a restricted request without a checker")
+	}
+}
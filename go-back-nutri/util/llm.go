@@ -1,71 +1,115 @@
 package util
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strconv"
 
+	"github.com/MishraShardendu22/cache"
 	"github.com/MishraShardendu22/models"
-	"github.com/go-resty/resty/v2"
+	"github.com/MishraShardendu22/provider"
 )
 
-func LLM(diet string, openAI_API string) models.NutritionalData {
-	messages := []map[string]any{
-		{"role": "system", "content": models.SystemGrade},
-		{"role": "user", "content": diet},
-	}
+// LLMCache holds LLM and TT results; nil disables caching.
+var LLMCache *cache.Cache
 
-	payload := map[string]any{
-		"model":    "deepseek/deepseek-chat-v3-0324:free",
-		"messages": messages,
-	}
+// LLM estimates every item in diet. Results are cached by the normalised diet,
+// the model and the prompt version.
+func LLM(ctx context.Context, p provider.Provider, diet string) ([]models.FoodItem, error) {
+	key := cache.Key(cache.KindFood, p.Model(), cache.PromptVersion(models.SystemGrade), cache.NormaliseDiet(diet))
+	return cache.Do(ctx, LLMCache, cache.KindFood, key, func() ([]models.FoodItem, error) {
+		return llm(ctx, p, diet)
+	})
+}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		panic("Error marshalling JSON: " + err.Error())
+func llm(ctx context.Context, p provider.Provider, diet string) ([]models.FoodItem, error) {
+	messages := []provider.Message{
+		{Role: "system", Content: models.SystemGrade},
+		{Role: "user", Content: diet},
 	}
 
-	openAI_URL := "https://openrouter.ai/api/v1/chat/completions"
-
-	client := resty.New()
-	res, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Authorization", "Bearer "+openAI_API).
-		SetBody(jsonData).
-		Post(openAI_URL)
+	content, err := p.Complete(ctx, messages)
 	if err != nil {
-		panic("Error making request: " + err.Error())
-	}
-	if res.IsError() {
-		panic("Error response from OpenRouter: " + res.String())
+		return nil, err
 	}
 
-	var llmResp models.LLMResponse
-	if err := json.Unmarshal(res.Body(), &llmResp); err != nil {
-		panic("Error unmarshalling response: " + err.Error())
-	}
-	if len(llmResp.Choices) == 0 {
-		panic("No choices in response")
+	items := ParseFoodItems(content)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no food items in response", provider.ErrUnparseable)
 	}
 
-	data := llmResp.Choices[0].Message.Content
-	re := regexp.MustCompile(`\{[^}]+\}`)
-	data = re.FindString(data)
+	return items, nil
+}
 
-	var raw map[string]interface{}
-	if err := json.Unmarshal([]byte(data), &raw); err != nil {
-		panic("Invalid JSON structure from LLM: " + err.Error())
-	}
+// ParseFoodItems reads every flat JSON object in the model output, whether it
+// came back as loose objects or wrapped in an array. Objects that don't parse
+// are skipped so one malformed item doesn't drop the rest of the meal.
+func ParseFoodItems(content string) []models.FoodItem {
+	re := regexp.MustCompile(`\{[^{}]+\}`)
 
-	return models.NutritionalData{
-		Energy:              models.EnergyKJ(toFloat(raw["Energy"])),
-		Sugars:              models.SugarGram(toFloat(raw["Sugars"])),
-		Fibre:               models.FibreGram(toFloat(raw["Fibre"])),
-		Protein:             models.ProteinGram(toFloat(raw["Protein"])),
-		Fruits:              models.FruitsPercent(toFloat(raw["Fruits"])),
-		Sodium:              models.SodiumMilligram(toFloat(raw["Sodium"])),
-		SaturatedFattyAcids: models.SaturatedFattyAcidsGram(toFloat(raw["SaturatedFattyAcids"])),
+	var items []models.FoodItem
+	for _, obj := range re.FindAllString(content, -1) {
+		var raw map[string]interface{}
+		if err := json.Unmarshal([]byte(obj), &raw); err != nil {
+			continue
+		}
+
+		grams := toFloat(raw["Grams"])
+		if grams <= 0 {
+			grams = 100
+		}
+
+		var micros *models.Micronutrients
+		if _, ok := raw["Iron"]; ok {
+			micros = &models.Micronutrients{
+				Iron:       toFloat(raw["Iron"]),
+				Calcium:    toFloat(raw["Calcium"]),
+				VitaminA:   toFloat(raw["VitaminA"]),
+				VitaminC:   toFloat(raw["VitaminC"]),
+				VitaminD:   toFloat(raw["VitaminD"]),
+				VitaminB12: toFloat(raw["VitaminB12"]),
+				Folate:     toFloat(raw["Folate"]),
+				Potassium:  toFloat(raw["Potassium"]),
+				Zinc:       toFloat(raw["Zinc"]),
+			}
+		}
+
+		var gi *float64
+		if v, ok := raw["GlycaemicIndex"]; ok && v != nil {
+			f := toFloat(v)
+			gi = &f
+		}
+
+		name, _ := raw["Name"].(string)
+		isWater, _ := raw["IsWater"].(bool)
+		isBeverage, _ := raw["IsBeverage"].(bool)
+		isCheese, _ := raw["IsCheese"].(bool)
+
+		items = append(items, models.FoodItem{
+			Name:  name,
+			Grams: grams,
+			Data: models.NutritionalData{
+				IsWater:             isWater,
+				IsBeverage:          isBeverage,
+				IsCheese:            isCheese,
+				Energy:              models.EnergyKJ(toFloat(raw["Energy"])),
+				Sugars:              models.SugarGram(toFloat(raw["Sugars"])),
+				Fibre:               models.FibreGram(toFloat(raw["Fibre"])),
+				Protein:             models.ProteinGram(toFloat(raw["Protein"])),
+				Fruits:              models.FruitsPercent(toFloat(raw["Fruits"])),
+				Sodium:              models.SodiumMilligram(toFloat(raw["Sodium"])),
+				SaturatedFattyAcids: models.SaturatedFattyAcidsGram(toFloat(raw["SaturatedFattyAcids"])),
+				Fat:                 models.FatGram(toFloat(raw["Fat"])),
+				Carbohydrate:        models.CarbohydrateGram(toFloat(raw["Carbohydrate"])),
+				GlycaemicIndex:      gi,
+				Micronutrients:      micros,
+			},
+		})
 	}
+
+	return items
 }
 
 func toFloat(v interface{}) float64 {
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MishraShardendu22/cache"
+	"github.com/MishraShardendu22/provider"
+)
+
+func TestLLMWithFakeProvider(t *testing.T) {
+	items, err := LLM(context.Background(), provider.NewFake(), "rice, dal, 2 rotis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+
+	again, _ := LLM(context.Background(), provider.NewFake(), "rice, dal, 2 rotis")
+	for i := range items {
+		if !reflect.DeepEqual(items[i], again[i]) {
+			t.Errorf("Expected deterministic output for %s, got %+v and %+v", items[i].Name, items[i], again[i])
+		}
+	}
+}
+
+func TestParseFoodItemsKeepsEveryObject(t *testing.T) {
+	content := `[{"Name": "rice", "Grams": 150, "Energy": 540}, {"Name": "dal", "Energy": 480}, {broken}]`
+
+	items := ParseFoodItems(content)
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+	if items[1].Grams != 100 {
+		t.Errorf("Expected missing grams to default to 100, got %v", items[1].Grams)
+	}
+}
+
+func TestLLMUsesCache(t *testing.T) {
+	LLMCache = cache.New(cache.NewLRU(10), time.Minute)
+	defer func() { LLMCache = nil }()
+
+	reply := `{"Name": "orange", "Grams": 130, "Energy": 197}`
+	p := &scripted{replies: []string{reply, reply}}
+
+	if _, err := LLM(context.Background(), p, "Orange, milk"); err != nil {
+		t.Fatal(err)
+	}
+	items, err := LLM(context.Background(), p, "milk,orange")
+	if err != nil || p.calls != 1 || items[0].Name != "orange" {
+		t.Errorf("Expected the second call served from cache, got %+v %v after %d calls", items, err, p.calls)
+	}
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MishraShardendu22/models"
+	"github.com/go-resty/resty/v2"
+)
+
+// RemoteTimeout bounds one call to the remote calculator.
+const RemoteTimeout = 10 * time.Second
+
+// remoteClient is shared so connections to the calculator are reused.
+var remoteClient = resty.New().SetTimeout(RemoteTimeout)
+
+// RemoteScore asks an external deployment of this service for the score and
+// grade. It is only used when a remote calculator is configured explicitly,
+// and needs a deployment that serves /api/calculate-nutrition/explain; older
+// ones that only return the grade are reported as an error.
+func RemoteScore(ctx context.Context, baseURL string, data models.NutritionalData, v models.Version) (models.NutritionalScore, string, error) {
+	// the same body /api/calculate-nutrition takes
+	body := struct {
+		models.NutritionalData
+		Version models.Version
+	}{data, v}
+
+	res, err := remoteClient.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		Post(baseURL + "/api/calculate-nutrition/explain")
+	if err != nil {
+		return models.NutritionalScore{}, "", fmt.Errorf("request error: %w", err)
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return models.NutritionalScore{}, "", fmt.Errorf("nutrition calculator at %s does not serve /api/calculate-nutrition/explain; it needs upgrading", baseURL)
+	}
+	if res.IsError() {
+		return models.NutritionalScore{}, "", fmt.Errorf("error response from nutrition calculator: %s", res.String())
+	}
+
+	var parsed struct {
+		Data models.ScoreExplanation `json:"data"`
+	}
+	if err := json.Unmarshal(res.Body(), &parsed); err != nil {
+		return models.NutritionalScore{}, "", fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	return parsed.Data.Score, parsed.Data.Grade, nil
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MishraShardendu22/models"
+)
+
+func TestRemoteScoreSendsCalcRequest(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/calculate-nutrition/explain" {
+			t.Errorf("Expected the explain endpoint, got %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"data": {"score": {"Value": 7, "Version": "2023"}, "grade": "C"}}`))
+	}))
+	defer srv.Close()
+
+	data := models.NutritionalData{Energy: 900, Sodium: 400, Fat: 12}
+	ns, grade, err := RemoteScore(context.Background(), srv.URL, data, models.V2023)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns.Value != 7 || grade != "C" {
+		t.Errorf("Expected score 7 and grade C, got %d and %s", ns.Value, grade)
+	}
+	if got["Energy"] != 900.0 || got["Fat"] != 12.0 || got["Version"] != "2023" {
+		t.Errorf("Expected the nutritional data and version at the top level, got %v", got)
+	}
+}
+
+func TestRemoteScoreStopsWithContext(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := RemoteScore(ctx, srv.URL, models.NutritionalData{}, models.V2017); err == nil {
+		t.Error("Expected an error once the context is done")
+	}
+}
+
+func TestRemoteScoreOldCalculator(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, _, err := RemoteScore(context.Background(), srv.URL, models.NutritionalData{}, models.V2017)
+	if err == nil || !strings.Contains(err.Error(), "explain") {
+		t.Errorf("Expected an error naming the missing endpoint, got %v", err)
+	}
+}
@@ -19,3 +19,24 @@ func GetPointsFromRange(v float64, steps []float64) int {
 	}
 	return 0
 }
+
+// GetBandFromRange returns the same points as GetPointsFromRange along with the
+// band the value fell into: above lower and at most upper. A nil bound means
+// the band is open on that side.
+func GetBandFromRange(v float64, steps []float64) (int, *float64, *float64) {
+	for i, l := range steps {
+		if v > l {
+			lower := l
+			if i == 0 {
+				return len(steps), &lower, nil
+			}
+			upper := steps[i-1]
+			return len(steps) - i, &lower, &upper
+		}
+	}
+	if len(steps) == 0 {
+		return 0, nil, nil
+	}
+	upper := steps[len(steps)-1]
+	return 0, nil, &upper
+}